@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ai.go - AI tools (ai: section)
+// AI tools start in the focused project's directory unless they have a cwd,
+// take a prompt (fixed, or asked for before each launch) and can open beside
+// the editor pane of the current tmux session (spawn: beside-editor)
+
+// promptAsk is the prompt: value that asks for the prompt before launch
+const promptAsk = "ask"
+
+// editorCommands are pane commands taken for an editor, besides $VISUAL and $EDITOR
+var editorCommands = []string{"nvim", "vim", "vi", "hx", "helix", "emacs", "nano", "micro", "kak"}
+
+// aiItems marks items (and those in sub-categories) as AI tools
+func aiItems(items []launchItem) []launchItem {
+	for i := range items {
+		items[i].IsAI = true
+		items[i].Children = aiItems(items[i].Children)
+	}
+	return items
+}
+
+// focusedProjectDir returns the directory of the project or worktree under the project cursor
+// It is "" when the cursor isn't inside a project
+func (m model) focusedProjectDir() string {
+	if m.projectCursor >= len(m.projectTreeItems) {
+		return ""
+	}
+
+	// Walk up through the cursor's ancestors, innermost first
+	depth := m.projectTreeItems[m.projectCursor].depth + 1
+	for i := m.projectCursor; i >= 0 && depth > 0; i-- {
+		ti := m.projectTreeItems[i]
+		if ti.depth >= depth {
+			continue
+		}
+		depth = ti.depth
+		if ti.item.ItemType == typeCategory && ti.item.Cwd != "" {
+			return ti.item.Cwd
+		}
+	}
+	return ""
+}
+
+// contextItem prepares an AI tool for launch: it runs in the focused project
+// unless it has a cwd, with prompt added to its command ("ask" adds none)
+// Other items are returned unchanged
+func (m model) contextItem(item launchItem, prompt string) launchItem {
+	if !item.IsAI {
+		return item
+	}
+	if item.Cwd == "" {
+		item.Cwd = m.focusedProjectDir()
+	}
+	if prompt != promptAsk {
+		item.Command = withPrompt(item.Command, item.PromptFlag, prompt)
+	}
+	return item
+}
+
+// withPrompt appends a prompt to command, after flag if there is one
+func withPrompt(command, flag, prompt string) string {
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		return command
+	}
+	if flag != "" {
+		command += " " + flag
+	}
+	return command + " " + shellQuote(prompt)
+}
+
+// isEditor reports whether a pane's current command is an editor
+func isEditor(command string) bool {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 && filepath.Base(fields[0]) == command {
+			return true
+		}
+	}
+	for _, editor := range editorCommands {
+		if editor == command {
+			return true
+		}
+	}
+	return false
+}
+
+// findEditorPane finds a pane of the current tmux session running an editor
+// A pane inside dir wins over other editor panes; it returns "" when there is none
+func findEditorPane(dir string) (paneID, editor string) {
+	output, err := tmuxOutput("list-panes", "-s", "-F", "#{pane_id}\t#{pane_current_command}\t#{pane_current_path}")
+	if err != nil {
+		return "", ""
+	}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 || !isEditor(fields[1]) {
+			continue
+		}
+		path, root := filepath.Clean(fields[2]), filepath.Clean(dir)
+		if dir != "" && (path == root || strings.HasPrefix(path, root+string(filepath.Separator))) {
+			return fields[0], fields[1]
+		}
+		if paneID == "" {
+			paneID, editor = fields[0], fields[1]
+		}
+	}
+	return paneID, editor
+}
+
+// tmuxBesideEditor splits the editor pane of the current session, or the launcher's pane without one
+func (s *spawner) tmuxBesideEditor(item launchItem) (string, error) {
+	paneID, editor := findEditorPane(item.Cwd)
+	if paneID == "" {
+		s.note("no editor pane in this session, splitting the launcher's pane")
+		return s.tmuxSplitHorizontal(item)
+	}
+	s.note(fmt.Sprintf("beside editor pane %s (%s)", paneID, editor))
+
+	cwd := item.Cwd
+	if cwd == "" {
+		cwd = os.Getenv("HOME")
+	}
+	return s.tmuxOutput(append([]string{"split-window", "-h", "-t", paneID, "-c", cwd, "-P", "-F", "#{pane_id}", "sh", "-c", item.Command},
+		remainOnExitArgs(item)...)...)
+}
+
+// writeAIInfo shows where an AI tool starts and what prompt it gets in the info pane
+func writeAIInfo(info *strings.Builder, item launchItem, prompt string) {
+	if item.Cwd == "" {
+		info.WriteString("Context: ~ (focus a project to start there)\n")
+	} else {
+		info.WriteString(fmt.Sprintf("Context: %s\n", item.Cwd))
+	}
+	switch prompt {
+	case "":
+	case promptAsk:
+		info.WriteString("Prompt: asked on launch\n")
+	default:
+		info.WriteString(fmt.Sprintf("Prompt: %s\n", prompt))
+	}
+}
+
+// openAIPrompt asks for the prompt of an AI tool before launching it
+func (m *model) openAIPrompt(item launchItem) {
+	m.showAIPrompt = true
+	m.aiItem = m.contextItem(item, promptAsk)
+	m.aiPrompt = ""
+}
+
+// updateAIPrompt handles keys while the AI prompt is open
+// An empty prompt launches the tool without one
+func (m model) updateAIPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var action promptAction
+	m.aiPrompt, action = editPrompt(m.aiPrompt, msg)
+	switch action {
+	case promptQuit:
+		return m, tea.Quit
+
+	case promptCancel:
+		m.showAIPrompt = false
+
+	case promptSubmit:
+		m.showAIPrompt = false
+		item := m.aiItem
+		item.Command = withPrompt(item.Command, item.PromptFlag, m.aiPrompt)
+		return m, m.launchCommand(item)
+	}
+	return m, nil
+}
+
+// viewAIPrompt renders the AI prompt centered over the screen
+func (m model) viewAIPrompt() string {
+	item := m.aiItem
+	dir := item.Cwd
+	if dir == "" {
+		dir = "~ (no project focused)"
+	}
+
+	return m.viewPromptBox(
+		m.theme.heading.Render("Prompt for "+item.Name),
+		"",
+		promptField("> ", m.aiPrompt),
+		"",
+		"Directory: "+dir,
+		"Command: "+withPrompt(item.Command, item.PromptFlag, m.aiPrompt),
+		"",
+		"Enter: launch  Esc: cancel  (an empty prompt starts the tool without one)",
+	)
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// dialog.go - Spawn dialog for batch launches
+// Pick a layout (with preview), the order of items across panes, and the spawn target
+
+// openSpawnDialog shows the dialog for launching items
+// The layout cursor starts on the last layout used, if it's suggested for this count
+func (m *model) openSpawnDialog(items []launchItem) {
+	m.showSpawnDialog = true
+	m.dialogItems = items
+	m.dialogCursor = 0
+	m.dialogSection = sectionLayouts
+	m.dialogTarget = m.spawnTarget
+
+	m.layoutCursor = 0
+	for i, option := range suggestLayouts(len(items)) {
+		if option.layout == m.selectedLayout {
+			m.layoutCursor = i
+			break
+		}
+	}
+}
+
+// updateSpawnDialog handles keys while the spawn dialog is open
+func (m model) updateSpawnDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	options := suggestLayouts(len(m.dialogItems))
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Dismiss, m.keys.Quit):
+		m.showSpawnDialog = false
+		m.dialogItems = nil
+
+	case msg.String() == "tab":
+		m.dialogSection = (m.dialogSection + 1) % (sectionTarget + 1)
+
+	case msg.String() == "shift+tab":
+		m.dialogSection = (m.dialogSection + sectionTarget) % (sectionTarget + 1)
+
+	case key.Matches(msg, m.keys.Up):
+		switch m.dialogSection {
+		case sectionLayouts:
+			if m.layoutCursor > 0 {
+				m.layoutCursor--
+			}
+		case sectionPanes:
+			if m.dialogCursor > 0 {
+				m.dialogCursor--
+			}
+		case sectionTarget:
+			m.dialogTarget = (m.dialogTarget + targetSwitchSession) % (targetSwitchSession + 1)
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		switch m.dialogSection {
+		case sectionLayouts:
+			if m.layoutCursor < len(options)-1 {
+				m.layoutCursor++
+			}
+		case sectionPanes:
+			if m.dialogCursor < len(m.dialogItems)-1 {
+				m.dialogCursor++
+			}
+		case sectionTarget:
+			m.dialogTarget = (m.dialogTarget + 1) % (targetSwitchSession + 1)
+		}
+
+	case key.Matches(msg, m.keys.MoveItemUp):
+		// Move the highlighted item one pane earlier
+		if m.dialogSection == sectionPanes && m.dialogCursor > 0 {
+			m.swapDialogItems(m.dialogCursor, m.dialogCursor-1)
+			m.dialogCursor--
+		}
+
+	case key.Matches(msg, m.keys.MoveItemDown):
+		// Move the highlighted item one pane later
+		if m.dialogSection == sectionPanes && m.dialogCursor < len(m.dialogItems)-1 {
+			m.swapDialogItems(m.dialogCursor, m.dialogCursor+1)
+			m.dialogCursor++
+		}
+
+	case key.Matches(msg, m.keys.SpawnTarget):
+		// Same key as the main view, from any section
+		m.dialogTarget = (m.dialogTarget + 1) % (targetSwitchSession + 1)
+
+	case key.Matches(msg, m.keys.Launch):
+		// Remember the choices for the next launch
+		m.selectedLayout = options[m.layoutCursor].layout
+		m.spawnTarget = m.dialogTarget
+		m.showSpawnDialog = false
+
+		items := m.dialogItems
+		m.dialogItems = nil
+		layout, opts := m.selectedLayout, batchOptions{target: m.spawnTarget}
+		return m, m.startLaunch(fmt.Sprintf("%d selected", len(items)), items, items, func(s *spawner) spawnCompleteMsg {
+			return s.multiple(items, layout, opts)
+		})
+	}
+
+	return m, nil
+}
+
+// spawnDialogHints is the hint line of the spawn dialog
+func (m model) spawnDialogHints() string {
+	k := m.keys
+	return joinHints(
+		"tab: section",
+		keyHint(k.nav(), "choose"),
+		keyHint(firstKeys(k.MoveItemUp, k.MoveItemDown), "move item"),
+		keyHint(k.SpawnTarget, "target"),
+		keyHint(k.Launch, "launch"),
+		keyHint(firstKeys(k.Dismiss, k.Quit), "cancel"),
+	)
+}
+
+// swapDialogItems exchanges the panes two items will land in
+func (m *model) swapDialogItems(i, j int) {
+	items := append([]launchItem(nil), m.dialogItems...)
+	items[i], items[j] = items[j], items[i]
+	m.dialogItems = items
+}
+
+// viewSpawnDialog renders the spawn dialog centered over the screen
+func (m model) viewSpawnDialog() string {
+	options := suggestLayouts(len(m.dialogItems))
+	heading := m.theme.heading
+
+	// sectionTitle marks the focused section
+	sectionTitle := func(section dialogSection) string {
+		if section == m.dialogSection {
+			return heading.Render("> " + section.String())
+		}
+		return "  " + section.String()
+	}
+
+	// Layout list beside the highlighted layout's preview
+	var layoutLines []string
+	layoutLines = append(layoutLines, sectionTitle(sectionLayouts))
+	for i, option := range options {
+		prefix := "   "
+		if i == m.layoutCursor {
+			prefix = " ● "
+		}
+		line := fmt.Sprintf("%s%s - %s", prefix, option.name, option.description)
+		if i == m.layoutCursor && m.dialogSection == sectionLayouts {
+			line = m.theme.cursor.Render(line)
+		}
+		layoutLines = append(layoutLines, line)
+	}
+	layouts := lipgloss.JoinHorizontal(lipgloss.Top,
+		strings.Join(layoutLines, "\n"),
+		"    ",
+		options[m.layoutCursor].preview,
+	)
+
+	// Items in pane order (numbers match the preview)
+	paneLines := []string{sectionTitle(sectionPanes)}
+	for i, item := range m.dialogItems {
+		prefix := "   "
+		if i == m.dialogCursor && m.dialogSection == sectionPanes {
+			prefix = " ▶ "
+		}
+		line := fmt.Sprintf("%s%d. %s", prefix, i+1, item.Name)
+		if i == m.dialogCursor && m.dialogSection == sectionPanes {
+			line = m.theme.cursor.Render(line)
+		}
+		paneLines = append(paneLines, line)
+	}
+
+	target := sectionTitle(sectionTarget) + ": ◀ " + m.dialogTarget.String() + " ▶"
+
+	help := m.spawnDialogHints()
+	if m.dryRun {
+		dry := "Dry run"
+		if m.keys.Launch.Enabled() {
+			dry += " - " + m.keys.Launch.Help().Key + " shows the plan"
+		}
+		help = dry + "  |  " + help
+	}
+
+	return m.viewPromptBox(
+		heading.Render(fmt.Sprintf("Launch %d items", len(m.dialogItems))),
+		"",
+		layouts,
+		"",
+		strings.Join(paneLines, "\n"),
+		"",
+		target,
+		"",
+		help,
+	)
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// docs.go - Item documentation for the info pane
+// An item's info_file (markdown) wins; otherwise its man page. --help output
+// is only loaded on request (H): moving the cursor must not run programs
+
+const (
+	docCommandTimeout = 2 * time.Second
+	maxDocLines       = 1000
+)
+
+var (
+	docHeadingStyle = lipgloss.NewStyle().Bold(true)
+
+	// Terminal formatting that man/--help output may still contain
+	ansiPattern       = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+	overstrikePattern = regexp.MustCompile(`.\x08`)
+
+	// Inline markdown
+	mdImagePattern  = regexp.MustCompile(`!\[([^\]]*)\]\(([^)]+)\)`)
+	mdLinkPattern   = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+	mdStrongPattern = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	mdEmPattern     = regexp.MustCompile(`(^|\W)[*_]([^*_]+)[*_]`)
+	mdCodePattern   = regexp.MustCompile("`([^`]+)`")
+	mdHeading       = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	mdBullet        = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdOrdered       = regexp.MustCompile(`^(\s*)(\d+)[.)]\s+(.*)$`)
+	mdRule          = regexp.MustCompile(`^(-\s*){3,}$|^(\*\s*){3,}$|^(_\s*){3,}$`)
+)
+
+// needsDoc reports whether an item has anything to look up docs for
+func needsDoc(item launchItem) bool {
+	return item.ItemType == typeCommand && (item.InfoFile != "" || item.Command != "")
+}
+
+// requestDoc starts loading docs for the item in the info pane, once per item
+func (m *model) requestDoc() tea.Cmd {
+	item, ok := m.currentItem()
+	if !ok || !needsDoc(item) {
+		return nil
+	}
+	if _, loaded := m.docs[item.Path]; loaded {
+		return nil
+	}
+
+	m.docs[item.Path] = itemDoc{loading: true}
+	return func() tea.Msg {
+		return docLoadedMsg{path: item.Path, doc: findDoc(item)}
+	}
+}
+
+// findDoc looks up an item's documentation
+func findDoc(item launchItem) itemDoc {
+	if item.InfoFile != "" {
+		path := infoFilePath(item)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return itemDoc{source: "info_file", text: fmt.Sprintf("Could not read %s: %v", path, err)}
+		}
+		return itemDoc{source: "info_file", text: string(data), markdown: true}
+	}
+
+	bin := commandBinary(item.Command)
+	if bin == "" {
+		return itemDoc{}
+	}
+	if text := manPage(bin); text != "" {
+		return itemDoc{source: "man", text: text}
+	}
+	return itemDoc{helpBin: bin}
+}
+
+// loadHelpDoc runs "bin --help" for the info pane's command, on request only
+// Not every program treats --help as harmless, so browsing never runs it
+func (m *model) loadHelpDoc() tea.Cmd {
+	item, ok := m.currentItem()
+	if !ok {
+		return nil
+	}
+	doc := m.docs[item.Path]
+	if doc.helpBin == "" {
+		return nil
+	}
+
+	bin := doc.helpBin
+	m.docs[item.Path] = itemDoc{loading: true}
+	return func() tea.Msg {
+		text := helpOutput(bin, item.Cwd)
+		if text == "" {
+			text = bin + " --help printed nothing"
+		}
+		return docLoadedMsg{path: item.Path, doc: itemDoc{source: "--help", text: text}}
+	}
+}
+
+// infoFilePath resolves info_file (~ expanded, relative to the item's cwd)
+func infoFilePath(item launchItem) string {
+	path := expandPath(item.InfoFile)
+	if !filepath.IsAbs(path) && item.Cwd != "" {
+		path = filepath.Join(item.Cwd, path)
+	}
+	return path
+}
+
+// commandBinary returns the program a command runs, if it is installed on $PATH
+// Paths (./deploy.sh) are skipped: they have no man page, and --help may not be harmless
+func commandBinary(command string) string {
+	for _, field := range strings.Fields(command) {
+		if strings.Contains(field, "=") && !strings.HasPrefix(field, "=") {
+			continue // Leading VAR=value assignments
+		}
+		if strings.Contains(field, "/") {
+			return ""
+		}
+		if _, err := exec.LookPath(field); err != nil {
+			return ""
+		}
+		return field
+	}
+	return ""
+}
+
+// manPage returns a program's man page as plain text ("" if there is none)
+func manPage(bin string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), docCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "man", "-P", "cat", bin)
+	cmd.Env = append(os.Environ(), "MANWIDTH=80", "MAN_KEEP_FORMATTING=0")
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return cleanDocText(string(output))
+}
+
+// helpOutput returns what "bin --help" prints ("" if nothing)
+// Many tools print help on stderr or exit non-zero, so both are accepted
+func helpOutput(bin, dir string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), docCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, bin, "--help")
+	cmd.Dir = dir
+	output, _ := cmd.CombinedOutput()
+	return cleanDocText(string(output))
+}
+
+// cleanDocText strips terminal formatting and caps the length of command output
+func cleanDocText(text string) string {
+	text = overstrikePattern.ReplaceAllString(text, "")
+	text = ansiPattern.ReplaceAllString(text, "")
+	text = strings.ReplaceAll(text, "\t", "    ")
+
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if len(lines) > maxDocLines {
+		lines = append(lines[:maxDocLines], "…")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// docLines renders the description, repo and docs of the info pane's item
+func (m model) docLines(width int) []string {
+	var lines []string
+
+	info := m.currentInfo
+	if info.description != "" {
+		lines = append(lines, "")
+		lines = append(lines, wrapText(info.description, width, "", "")...)
+	}
+	if info.repo != "" {
+		lines = append(lines, "", truncateText("Repo: "+info.repo, width))
+	}
+
+	doc, ok := m.docs[m.infoPath]
+	switch {
+	case !ok:
+		return lines
+	case doc.loading:
+		return append(lines, "", m.spinner.View()+" Loading docs…")
+	case doc.helpBin != "":
+		hint := withHint("No man page for "+doc.helpBin, keyHint(m.keys.HelpOutput, "show its --help output"))
+		return append(lines, "", truncateText(hint, width))
+	case doc.source == "":
+		return lines
+	}
+
+	title := "📖 Docs"
+	switch doc.source {
+	case "info_file":
+		title += " (" + info.mdPath + ")"
+	case "man":
+		title += " (man page)"
+	case "--help":
+		title += " (--help)"
+	}
+	lines = append(lines, "", truncateText(title, width), "")
+
+	if doc.markdown {
+		return append(lines, renderMarkdown(doc.text, width)...)
+	}
+	return append(lines, renderPreformatted(info.cliFlags, width)...)
+}
+
+// renderPreformatted wraps command output, keeping each line's indentation
+func renderPreformatted(text string, width int) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if lipgloss.Width(line) <= width {
+			lines = append(lines, line)
+			continue
+		}
+		trimmed := strings.TrimLeft(line, " ")
+		indent := line[:len(line)-len(trimmed)]
+		if len(indent) > width/2 {
+			indent = indent[:width/2]
+		}
+		lines = append(lines, wrapText(trimmed, width, indent, indent)...)
+	}
+	return lines
+}
+
+// renderMarkdown renders the markdown subset docs use: headings, paragraphs,
+// lists, block quotes, rules and code blocks, word-wrapped to width
+func renderMarkdown(src string, width int) []string {
+	var lines []string
+	var paragraph []string
+	inCode := false
+
+	// blank adds a separating empty line (never two in a row)
+	blank := func() {
+		if len(lines) > 0 && lines[len(lines)-1] != "" {
+			lines = append(lines, "")
+		}
+	}
+	flush := func() {
+		if len(paragraph) > 0 {
+			lines = append(lines, wrapText(inlineMarkdown(strings.Join(paragraph, " ")), width, "", "")...)
+			paragraph = nil
+		}
+	}
+
+	for _, raw := range strings.Split(src, "\n") {
+		line := strings.TrimRight(strings.ReplaceAll(raw, "\t", "    "), " \r")
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			flush()
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			// Code keeps its layout; long lines are cut rather than wrapped
+			lines = append(lines, truncateText("  "+line, width))
+			continue
+		}
+
+		if match := mdHeading.FindStringSubmatch(trimmed); match != nil {
+			flush()
+			blank()
+			text := truncateText(inlineMarkdown(match[2]), width)
+			lines = append(lines, docHeadingStyle.Render(text))
+			if len(match[1]) <= 2 {
+				lines = append(lines, strings.Repeat("─", lipgloss.Width(text)))
+			}
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flush()
+			blank()
+
+		case mdRule.MatchString(trimmed):
+			flush()
+			lines = append(lines, strings.Repeat("─", width))
+
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			text := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			lines = append(lines, wrapText(inlineMarkdown(text), width, "│ ", "│ ")...)
+
+		case mdBullet.MatchString(line):
+			flush()
+			match := mdBullet.FindStringSubmatch(line)
+			pad := listIndent(match[1])
+			lines = append(lines, wrapText(inlineMarkdown(match[2]), width, pad+"• ", pad+"  ")...)
+
+		case mdOrdered.MatchString(line):
+			flush()
+			match := mdOrdered.FindStringSubmatch(line)
+			pad := listIndent(match[1])
+			marker := match[2] + ". "
+			lines = append(lines, wrapText(inlineMarkdown(match[3]), width, pad+marker, pad+strings.Repeat(" ", len(marker)))...)
+
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+
+	// Drop a trailing blank line
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// listIndent converts a list item's leading spaces into nesting indentation
+func listIndent(spaces string) string {
+	return strings.Repeat("  ", len(spaces)/2)
+}
+
+// inlineMarkdown strips inline markup, keeping link targets visible
+func inlineMarkdown(text string) string {
+	text = mdImagePattern.ReplaceAllString(text, "[image: $1]")
+	text = mdLinkPattern.ReplaceAllString(text, "$1 ($2)")
+	text = mdCodePattern.ReplaceAllString(text, "$1")
+	text = mdStrongPattern.ReplaceAllString(text, "$2")
+	text = mdEmPattern.ReplaceAllString(text, "$1$2")
+	return text
+}
+
+// wrapText word-wraps text to width; first prefixes the first line, rest the others
+// Words longer than a line are split
+func wrapText(text string, width int, first, rest string) []string {
+	var lines []string
+	prefix := first
+	current := ""
+
+	emit := func() {
+		lines = append(lines, prefix+current)
+		prefix, current = rest, ""
+	}
+
+	for _, word := range strings.Fields(text) {
+		for {
+			room := width - lipgloss.Width(prefix)
+			if room < 1 {
+				room = 1
+			}
+
+			candidate := word
+			if current != "" {
+				candidate = current + " " + word
+			}
+			if lipgloss.Width(candidate) <= room {
+				current = candidate
+				break
+			}
+			if current != "" {
+				emit()
+				continue
+			}
+
+			// A single word wider than the line: split it
+			runes := []rune(word)
+			if len(runes) <= room {
+				current = word
+				break
+			}
+			current = string(runes[:room])
+			word = string(runes[room:])
+			emit()
+		}
+	}
+	if current != "" || len(lines) == 0 {
+		emit()
+	}
+	return lines
+}
+
+// truncateText cuts text to width, marking the cut with "…"
+func truncateText(text string, width int) string {
+	if lipgloss.Width(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
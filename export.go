@@ -0,0 +1,264 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// export.go - Exporting a project's profiles as a tmuxinator or tmuxp file
+// The project becomes one session with a window per profile, so people
+// without the launcher can start the same panes. Launcher-only settings
+// (staging, run_as process, after hooks, session handling) are reported
+
+// tmuxinatorProject is the part of a tmuxinator project file the export writes
+type tmuxinatorProject struct {
+	Name           string           `yaml:"name"`
+	Root           string           `yaml:"root,omitempty"`
+	OnProjectStart []string         `yaml:"on_project_start,omitempty"`
+	Windows        []map[string]any `yaml:"windows"`
+}
+
+// tmuxinatorWindow is a tmuxinator window with panes
+type tmuxinatorWindow struct {
+	Layout string `yaml:"layout"`
+	Root   string `yaml:"root,omitempty"`
+	Panes  []any  `yaml:"panes"`
+}
+
+// tmuxpSession is the part of a tmuxp session file the export writes
+type tmuxpSession struct {
+	SessionName    string        `yaml:"session_name"`
+	StartDirectory string        `yaml:"start_directory,omitempty"`
+	BeforeScript   string        `yaml:"before_script,omitempty"`
+	Windows        []tmuxpWindow `yaml:"windows"`
+}
+
+// tmuxpWindow is a tmuxp window
+type tmuxpWindow struct {
+	WindowName     string      `yaml:"window_name"`
+	Layout         string      `yaml:"layout"`
+	StartDirectory string      `yaml:"start_directory,omitempty"`
+	Panes          []tmuxpPane `yaml:"panes"`
+}
+
+// tmuxpPane is a tmuxp pane
+type tmuxpPane struct {
+	ShellCommand   []string `yaml:"shell_command,omitempty"`
+	StartDirectory string   `yaml:"start_directory,omitempty"`
+}
+
+// exporter converts one project, collecting warnings as it goes
+type exporter struct {
+	warnings []string
+}
+
+// warn records a setting that tmuxinator/tmuxp can't express
+func (ex *exporter) warn(format string, args ...any) {
+	ex.warnings = append(ex.warnings, fmt.Sprintf(format, args...))
+}
+
+// exportProject converts project (only the named profile if profile isn't "")
+// into a tmuxinator or tmuxp file
+func exportProject(project ProjectConfig, profile, format string) ([]byte, []string, error) {
+	profiles := project.Profiles
+	if profile != "" {
+		profiles = nil
+		for _, p := range project.Profiles {
+			if p.Name == profile {
+				profiles = append(profiles, p)
+			}
+		}
+		if len(profiles) == 0 {
+			return nil, nil, fmt.Errorf("project %q has no profile %q", project.Name, profile)
+		}
+	}
+	if len(profiles) == 0 {
+		return nil, nil, fmt.Errorf("project %q has no profiles to export", project.Name)
+	}
+
+	ex := &exporter{}
+	if len(project.Commands) > 0 {
+		ex.warn("%d command(s) of the project are not exported (only profiles are)", len(project.Commands))
+	}
+	if len(project.After) > 0 {
+		ex.warn("the project's after hooks are not exported")
+	}
+	for _, p := range profiles {
+		ex.checkProfile(p)
+	}
+
+	var file any
+	switch format {
+	case "tmuxinator":
+		file = ex.tmuxinator(project, profiles)
+	case "tmuxp":
+		file = ex.tmuxp(project, profiles)
+	default:
+		return nil, nil, fmt.Errorf("unknown format %q (use tmuxinator or tmuxp)", format)
+	}
+	out, err := marshalYAML(file)
+	return out, ex.warnings, err
+}
+
+// checkProfile warns about a profile's settings that have no tmuxinator/tmuxp equivalent
+func (ex *exporter) checkProfile(profile ProfileConfig) {
+	where := fmt.Sprintf("profile %q", profile.Name)
+	if profile.Session != "" || profile.OnExists != "" || profile.Target != "" {
+		ex.warn("%s: session, on_exists and target are not exported (the file names its own session)", where)
+	}
+	if len(profile.After) > 0 {
+		ex.warn("%s: after hooks are not exported", where)
+	}
+	for i, pane := range profile.Panes {
+		paneWhere := fmt.Sprintf("%s pane %d", where, i+1)
+		if len(pane.DependsOn) > 0 || pane.ReadyWhen != nil {
+			ex.warn("%s: depends_on and ready_when are not exported, all panes start at once", paneWhere)
+		}
+		if parseRunMode(firstNonEmpty(pane.RunAs, profile.RunAs)) == runProcess {
+			ex.warn("%s: run_as process is exported as typed keys", paneWhere)
+		}
+	}
+}
+
+// tmuxinator builds a tmuxinator project; before hooks run at session start
+func (ex *exporter) tmuxinator(project ProjectConfig, profiles []ProfileConfig) tmuxinatorProject {
+	out := tmuxinatorProject{Name: project.Name, Root: project.Path, OnProjectStart: append([]string{}, project.Before...)}
+	for _, profile := range profiles {
+		out.OnProjectStart = append(out.OnProjectStart, profile.Before...)
+
+		// tmuxinator has a root per window, not per pane
+		window := tmuxinatorWindow{Layout: exportLayout(profile.Layout), Root: sharedCwd(profile.Panes, project.Path)}
+		for _, pane := range profile.Panes {
+			var commands []string
+			if pane.Cwd != "" && pane.Cwd != firstNonEmpty(window.Root, project.Path) {
+				commands = append(commands, "cd "+shellPath(pane.Cwd))
+			}
+			if pane.Command != "" {
+				commands = append(commands, pane.Command)
+			}
+
+			var entry any
+			switch {
+			case pane.Name != "":
+				entry = map[string][]string{pane.Name: commands}
+			case len(commands) == 1:
+				entry = commands[0]
+			case len(commands) > 1:
+				entry = commands
+			}
+			window.Panes = append(window.Panes, entry)
+		}
+		out.Windows = append(out.Windows, map[string]any{profile.Name: window})
+	}
+	return out
+}
+
+// tmuxp builds a tmuxp session; tmuxp runs a single before_script
+func (ex *exporter) tmuxp(project ProjectConfig, profiles []ProfileConfig) tmuxpSession {
+	out := tmuxpSession{SessionName: project.Name, StartDirectory: project.Path}
+
+	hooks := append([]string{}, project.Before...)
+	for _, profile := range profiles {
+		hooks = append(hooks, profile.Before...)
+	}
+	if len(hooks) > 0 {
+		out.BeforeScript = hooks[0]
+	}
+	if len(hooks) > 1 {
+		ex.warn("tmuxp runs one before_script: only %q is exported, %d more before hook(s) are not", hooks[0], len(hooks)-1)
+	}
+
+	for _, profile := range profiles {
+		window := tmuxpWindow{WindowName: profile.Name, Layout: exportLayout(profile.Layout)}
+		for _, pane := range profile.Panes {
+			p := tmuxpPane{}
+			if pane.Command != "" {
+				p.ShellCommand = []string{pane.Command}
+			}
+			if pane.Cwd != project.Path {
+				p.StartDirectory = pane.Cwd
+			}
+			window.Panes = append(window.Panes, p)
+		}
+		out.Windows = append(out.Windows, window)
+	}
+	return out
+}
+
+// exportLayout names a profile's layout for tmux (profiles default to tiled)
+func exportLayout(layout string) string {
+	return firstNonEmpty(layout, "tiled")
+}
+
+// sharedCwd returns the cwd all panes share if it differs from root, else ""
+func sharedCwd(panes []paneConfig, root string) string {
+	if len(panes) == 0 {
+		return ""
+	}
+	cwd := panes[0].Cwd
+	for _, pane := range panes[1:] {
+		if pane.Cwd != cwd {
+			return ""
+		}
+	}
+	if cwd == root {
+		return ""
+	}
+	return cwd
+}
+
+// shellPath quotes a path for a shell command, leaving a leading ~/ unquoted
+// so the shell of whoever runs the file expands it to their home
+func shellPath(path string) string {
+	if path == "~" {
+		return path
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		return "~/" + shellQuote(rest)
+	}
+	return shellQuote(path)
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// runExport handles "tui-launcher export [--format tmuxinator|tmuxp] <project> [profile]"
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", "tmuxinator", "file format: tmuxinator or tmuxp")
+	flags.Parse(args)
+	if flags.NArg() < 1 || flags.NArg() > 2 {
+		return fmt.Errorf("usage: tui-launcher export [--format tmuxinator|tmuxp] <project> [profile]")
+	}
+
+	loaded := loadConfig().(configLoadedMsg)
+	if loaded.err != nil {
+		return loaded.err
+	}
+	var names []string
+	for _, project := range loaded.config.Projects {
+		names = append(names, project.Name)
+		if project.Name != flags.Arg(0) {
+			continue
+		}
+		out, warnings, err := exportProject(project, flags.Arg(1), *format)
+		for _, warning := range warnings {
+			fmt.Fprintln(os.Stderr, "warning: "+warning)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(out))
+		return nil
+	}
+	return fmt.Errorf("no project %q (projects: %s)", flags.Arg(0), strings.Join(names, ", "))
+}
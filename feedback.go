@@ -0,0 +1,346 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// feedback.go - Launch progress, toasts and the error log
+// Launches never quit the launcher; results show up here instead
+
+const (
+	toastDuration   = 4 * time.Second
+	maxErrorLogSize = 200
+)
+
+// beginLaunch starts a progress view for a launch and returns its id
+// Items with before hooks start out pending; everything else is spawning
+func (m *model) beginLaunch(title string, items []launchItem, hookItems []launchItem) int {
+	m.launchSeq++
+
+	phase := phaseSpawning
+	if len(collectHooks(hookItems, "before")) > 0 {
+		phase = phasePending
+	}
+
+	entries := make([]launchEntry, len(items))
+	for i, item := range items {
+		entries[i] = launchEntry{name: item.Name, phase: phase}
+		if phase == phasePending {
+			entries[i].detail = "running before hooks"
+		}
+	}
+
+	m.launch = launchProgress{
+		id:      m.launchSeq,
+		title:   title,
+		entries: entries,
+		visible: true,
+	}
+	return m.launchSeq
+}
+
+// startLaunch launches items with their hooks and progress view
+// In dry-run mode it computes the spawn plan instead
+func (m *model) startLaunch(title string, items, hookItems []launchItem, run func(s *spawner) spawnCompleteMsg) tea.Cmd {
+	if m.dryRun {
+		return planCmd(func() *spawnPlan { return planLaunch(title, hookItems, run) })
+	}
+
+	id := m.beginLaunch(title, items, hookItems)
+	return withLaunchID(id, withHooks(hookItems, func() tea.Msg {
+		return run(liveSpawner)
+	}))
+}
+
+// launchProfile launches a profile's panes with its layout and options
+func (m *model) launchProfile(profile launchItem) tea.Cmd {
+	items := profileItems(profile)
+	opts := profileOptions(profile, m.spawnTarget)
+	return m.startLaunch(profile.Name, items, []launchItem{profile}, func(s *spawner) spawnCompleteMsg {
+		return s.multiple(items, profile.Layout, opts)
+	})
+}
+
+// launchCommand launches one command: in this terminal without tmux, otherwise with its spawn mode
+func (m *model) launchCommand(item launchItem) tea.Cmd {
+	if !m.useTmux {
+		// Non-tmux mode: run before hooks, then the command directly in current terminal
+		if m.dryRun {
+			return planCmd(func() *spawnPlan { return planDirect(item) })
+		}
+		return directHooks(item)
+	}
+	return m.startLaunch(item.Name, []launchItem{item}, []launchItem{item}, func(s *spawner) spawnCompleteMsg {
+		return s.single(item, item.DefaultSpawn)
+	})
+}
+
+// withLaunchID tags a spawn command's result with the launch it belongs to
+func withLaunchID(id int, spawn tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		msg := spawn()
+		if done, ok := msg.(spawnCompleteMsg); ok {
+			done.launchID = id
+			return done
+		}
+		return msg
+	}
+}
+
+// finishLaunch folds a spawn result into the progress view, toast and error log
+func (m *model) finishLaunch(msg spawnCompleteMsg) tea.Cmd {
+	if msg.err != nil {
+		m.logError(msg.err)
+	}
+
+	if msg.launchID == 0 || msg.launchID != m.launch.id {
+		// Not the launch on screen (focus/kill/restart, or superseded)
+		if msg.err != nil {
+			return m.showToast(m.withLogHint("✗ "+firstLine(msg.err.Error())), true)
+		}
+		return m.afterHookToast(msg)
+	}
+
+	// Panes come back in item order; a failure leaves the rest unstarted
+	created := len(msg.paneIDs)
+	if msg.proc != nil {
+		created = 1
+	}
+	for i := range m.launch.entries {
+		entry := &m.launch.entries[i]
+		switch {
+		case i < len(msg.paneIDs):
+			entry.paneID = msg.paneIDs[i]
+			entry.phase, entry.detail = phaseDone, ""
+		case i < created:
+			entry.phase, entry.detail = phaseDone, ""
+		case msg.err != nil && i == created:
+			entry.phase, entry.detail = phaseFailed, firstLine(msg.err.Error())
+		case msg.err != nil:
+			entry.phase, entry.detail = phaseFailed, "not started"
+		default:
+			// Nothing new was created (e.g. attached to an existing session)
+			entry.phase, entry.detail = phaseDone, ""
+		}
+	}
+
+	if msg.err != nil {
+		return m.showToast(m.withLogHint("✗ Launch failed: "+firstLine(msg.err.Error())), true)
+	}
+	// The hook warning replaces the success toast
+	return tea.Batch(m.checkLaunchDone(), m.afterHookToast(msg))
+}
+
+// withLogHint points a failure toast at the error log
+func (m model) withLogHint(text string) string {
+	return withHint(text, keyHint(m.keys.ErrorLog, "error log"))
+}
+
+// afterHookToast reports a failed after hook of a launch that succeeded
+func (m *model) afterHookToast(msg spawnCompleteMsg) tea.Cmd {
+	if msg.hookErr == nil {
+		return nil
+	}
+	m.logError(msg.hookErr)
+	return m.showToast(m.withLogHint("✗ Launched, but "+firstLine(msg.hookErr.Error())), true)
+}
+
+// applyStageToLaunch mirrors a staging update into the progress view
+func (m *model) applyStageToLaunch(msg stageMsg) tea.Cmd {
+	for i := range m.launch.entries {
+		entry := &m.launch.entries[i]
+		if entry.paneID != msg.paneID {
+			continue
+		}
+		switch msg.state {
+		case stateWaiting, stateStarting:
+			entry.phase, entry.detail = phasePending, msg.detail
+		case stateFailed:
+			entry.phase, entry.detail = phaseFailed, msg.detail
+			m.logError(fmt.Errorf("%s: %s", entry.name, msg.detail))
+			return m.showToast("✗ "+entry.name+": "+msg.detail, true)
+		default:
+			entry.phase, entry.detail = phaseDone, ""
+		}
+	}
+	return m.checkLaunchDone()
+}
+
+// checkLaunchDone hides a fully successful launch behind a success toast (or closes the popup)
+func (m *model) checkLaunchDone() tea.Cmd {
+	if !m.launch.visible {
+		return nil
+	}
+	for _, entry := range m.launch.entries {
+		if entry.phase != phaseDone {
+			return nil
+		}
+	}
+
+	m.launch.visible = false
+	if m.popup {
+		// The popup was opened for this launch; closing it shows the result
+		return tea.Quit
+	}
+	count := len(m.launch.entries)
+	noun := "item"
+	if count != 1 {
+		noun = "items"
+	}
+	return m.showToast(fmt.Sprintf("✓ Launched %s (%d %s)", m.launch.title, count, noun), false)
+}
+
+// showToast displays a one-line notification in place of the footer
+func (m *model) showToast(text string, isError bool) tea.Cmd {
+	m.toastSeq++
+	m.toastText = text
+	m.toastIsError = isError
+
+	id := m.toastSeq
+	return tea.Tick(toastDuration, func(t time.Time) tea.Msg {
+		return toastExpiredMsg{id: id}
+	})
+}
+
+// toastLine renders the toast cut to width columns (no scrolling)
+func (m model) toastLine(width int) string {
+	text := ""
+	if width > 0 {
+		text = ansi.Truncate(m.toastText, width, "…")
+	}
+	if m.toastIsError {
+		return m.theme.toastError.Render(text)
+	}
+	return m.theme.toast.Render(text)
+}
+
+// logError appends to the error log, dropping the oldest entries past maxErrorLogSize
+func (m *model) logError(err error) {
+	m.errorLog = append(m.errorLog, errorLogEntry{at: time.Now(), text: err.Error()})
+	if len(m.errorLog) > maxErrorLogSize {
+		m.errorLog = m.errorLog[len(m.errorLog)-maxErrorLogSize:]
+	}
+}
+
+// viewLaunchProgress renders the progress block shown at the top of the info pane
+func (m model) viewLaunchProgress() []string {
+	if !m.launch.visible {
+		return nil
+	}
+
+	done := 0
+	for _, entry := range m.launch.entries {
+		if entry.phase == phaseDone {
+			done++
+		}
+	}
+
+	lines := []string{fmt.Sprintf("Launching %s (%d/%d)", m.launch.title, done, len(m.launch.entries))}
+	for _, entry := range m.launch.entries {
+		var marker string
+		switch entry.phase {
+		case phasePending:
+			marker = "…"
+		case phaseSpawning:
+			marker = m.spinner.View()
+		case phaseDone:
+			marker = "✓"
+		case phaseFailed:
+			marker = "✗"
+		}
+		line := fmt.Sprintf("  %s %s [%s]", marker, entry.name, entry.phase)
+		if entry.detail != "" {
+			line += " " + entry.detail
+		}
+		lines = append(lines, line)
+	}
+	if hint := keyHint(m.keys.Dismiss, "dismiss"); hint != "" {
+		lines = append(lines, "  ("+hint+")")
+	}
+	return append(lines, "")
+}
+
+// errorLogLines flattens the error log into display lines, newest last
+func (m model) errorLogLines() []string {
+	var lines []string
+	for _, entry := range m.errorLog {
+		lines = append(lines, entry.at.Format("15:04:05")+" "+strings.Repeat("─", 20))
+		lines = append(lines, strings.Split(entry.text, "\n")...)
+	}
+	return lines
+}
+
+// errorLogHints is the hint line of the error log overlay
+func (m model) errorLogHints() string {
+	k := m.keys
+	return joinHints(
+		keyHint(k.nav(), "scroll"),
+		keyHint(firstKeys(k.PageUp, k.PageDown), "page"),
+		keyHint(firstKeys(k.ErrorLog, k.Dismiss, k.Quit), "close"),
+	)
+}
+
+// viewErrorLog renders the full-screen error log overlay
+func (m model) viewErrorLog() string {
+	var sb strings.Builder
+	sb.WriteString(joinHints(fmt.Sprintf("Error Log (%d)", len(m.errorLog)), m.errorLogHints()) + "\n\n")
+
+	lines := m.errorLogLines()
+	if len(lines) == 0 {
+		lines = []string{"(no errors)"}
+	}
+
+	// Leave room for the header (2 lines)
+	height := m.height - 2
+	if height < 1 {
+		height = 1
+	}
+
+	start := m.errorLogOffset
+	if start > len(lines)-1 {
+		start = len(lines) - 1
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + height
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	for _, line := range lines[start:end] {
+		// GOLDEN RULE #2: Truncate to prevent wrapping
+		if m.width > 1 && len([]rune(line)) > m.width-1 {
+			line = string([]rune(line)[:m.width-2]) + "…"
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	return sb.String()
+}
+
+// scrollErrorLog moves the error log viewport, clamped to its content
+func (m *model) scrollErrorLog(delta int) {
+	maxOffset := len(m.errorLogLines()) - (m.height - 2)
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+	m.errorLogOffset += delta
+	if m.errorLogOffset > maxOffset {
+		m.errorLogOffset = maxOffset
+	}
+	if m.errorLogOffset < 0 {
+		m.errorLogOffset = 0
+	}
+}
+
+// firstLine returns the first line of s
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestToastLine(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  string
+	}{
+		{"✓ Launched dev (3 items)", 40, "✓ Launched dev (3 items)"},
+		{"✓ Launched dev (3 items)", 10, "✓ Launche…"},
+		{"✗ Launch failed: no server", 3, "✗ …"},
+		{"✗ Launch failed", 1, "…"},
+		{"✗ Launch failed", 0, ""},
+		{"✗ Launch failed", -4, ""}, // Before the first WindowSizeMsg
+		{"… waiting for db", 2, "……"},
+	}
+
+	for _, tt := range tests {
+		m := model{toastText: tt.text}
+		got := m.toastLine(tt.width)
+		if got != tt.want {
+			t.Errorf("toastLine(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+		}
+		if !utf8.ValidString(got) || (tt.width >= 0 && lipgloss.Width(got) > tt.width) {
+			t.Errorf("toastLine(%q, %d) = %q doesn't fit", tt.text, tt.width, got)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// git.go - Background git status for projects
+// A fixed number of workers query projects and stream results back one
+// message at a time, so Update never waits on git
+
+const (
+	gitWorkers         = 4
+	gitCommandTimeout  = 5 * time.Second
+	gitRefreshInterval = 30 * time.Second
+)
+
+// gitTick schedules the next refresh
+func gitTick() tea.Cmd {
+	return tea.Tick(gitRefreshInterval, func(t time.Time) tea.Msg {
+		return gitTickMsg{}
+	})
+}
+
+// gitProjects returns the items whose directories get git status: projects and their worktrees
+func gitProjects(items []launchItem) []launchItem {
+	var projects []launchItem
+	for _, item := range items {
+		if item.ItemType == typeCategory && item.Cwd != "" {
+			projects = append(projects, item)
+		}
+		for _, child := range item.Children {
+			if child.IsWorktree {
+				projects = append(projects, child)
+			}
+		}
+	}
+	return projects
+}
+
+// refreshGitStatus starts a refresh of every project (nil if one is running or there's nothing to do)
+func (m *model) refreshGitStatus() tea.Cmd {
+	projects := gitProjects(m.projectItems)
+	if m.gitRefreshing || len(projects) == 0 {
+		return nil
+	}
+	m.gitRefreshing = true
+
+	updates := make(chan gitStatusMsg, len(projects))
+	go func() {
+		jobs := make(chan launchItem)
+		var wg sync.WaitGroup
+		for i := 0; i < gitWorkers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for project := range jobs {
+					updates <- gitStatusMsg{path: project.Path, status: readGitStatus(project.Cwd)}
+				}
+			}()
+		}
+
+		for _, project := range projects {
+			jobs <- project
+		}
+		close(jobs)
+		wg.Wait()
+		close(updates)
+	}()
+
+	return waitForGitStatus(updates)
+}
+
+// waitForGitStatus delivers the next result of a refresh to Update
+func waitForGitStatus(updates <-chan gitStatusMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return gitRefreshDoneMsg{}
+		}
+		msg.updates = updates
+		return msg
+	}
+}
+
+// readGitStatus queries the repository at dir
+func readGitStatus(dir string) gitStatus {
+	// One porcelain v2 call gives branch, upstream, ahead/behind and changed files
+	output, err := runGit(dir, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		// Not a repo (or the directory is gone): no badge, no error
+		if strings.Contains(err.Error(), "not a git repository") || strings.Contains(err.Error(), "cannot change to") {
+			return gitStatus{}
+		}
+		return gitStatus{isRepo: true, err: err}
+	}
+
+	status := gitStatus{isRepo: true}
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# branch.oid "):
+			oid := strings.TrimPrefix(line, "# branch.oid ")
+			if len(oid) > 7 {
+				oid = oid[:7]
+			}
+			status.commit = oid
+		case strings.HasPrefix(line, "# branch.head "):
+			if head := strings.TrimPrefix(line, "# branch.head "); head != "(detached)" {
+				status.branch = head
+			}
+		case strings.HasPrefix(line, "# branch.upstream "):
+			status.upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			fmt.Sscanf(strings.TrimPrefix(line, "# branch.ab "), "+%d -%d", &status.ahead, &status.behind)
+		case line != "" && !strings.HasPrefix(line, "#"):
+			status.dirty++
+		}
+	}
+
+	// No stash ref means no stashes; that error is expected
+	if output, err := runGit(dir, "rev-list", "--walk-reflogs", "--count", "refs/stash"); err == nil {
+		status.stashes, _ = strconv.Atoi(output)
+	}
+
+	// Errors only matter to the status call above
+	status.worktrees, _ = readWorktrees(dir)
+
+	// Fails in a repository without commits
+	if output, err := runGit(dir, "log", "-1", "--format=%s%x00%cr"); err == nil {
+		status.lastCommit, status.lastWhen, _ = strings.Cut(output, "\x00")
+	}
+
+	return status
+}
+
+// runGit runs a git command in dir and returns its trimmed output
+// Errors carry git's stderr
+func runGit(dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	// Polls run while the user works in the repo: never take index.lock just
+	// to refresh stat info, or their own git commands fail
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0")
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// gitBadge renders the compact status shown beside a project ("" if not a repo)
+// e.g. "main ↑2↓1 ●3 ⚑1", or "main ✓" when clean and in sync
+func (m model) gitBadge(path string) string {
+	status, ok := m.gitStatus[path]
+	if !ok || !status.isRepo {
+		return ""
+	}
+	if status.err != nil {
+		return m.icons.branch + " ?"
+	}
+
+	parts := []string{m.icons.branch + " " + status.headName()}
+	drift := ""
+	if status.ahead > 0 {
+		drift += fmt.Sprintf("%s%d", m.icons.ahead, status.ahead)
+	}
+	if status.behind > 0 {
+		drift += fmt.Sprintf("%s%d", m.icons.behind, status.behind)
+	}
+	if drift != "" {
+		parts = append(parts, drift)
+	}
+	if status.dirty > 0 {
+		parts = append(parts, fmt.Sprintf("%s%d", m.icons.dirty, status.dirty))
+	}
+	if status.stashes > 0 {
+		parts = append(parts, fmt.Sprintf("%s%d", m.icons.stash, status.stashes))
+	}
+	if len(parts) == 1 {
+		parts = append(parts, m.icons.clean)
+	}
+	return strings.Join(parts, " ")
+}
+
+// headName is the branch, or the short commit when detached
+func (s gitStatus) headName() string {
+	if s.branch != "" {
+		return s.branch
+	}
+	if s.commit != "" {
+		return "(" + s.commit + ")"
+	}
+	return "(no commits)"
+}
+
+// writeGitInfo adds a project's full git status to the info pane
+// newWorktreeHint tells how to add a worktree ("" when that key is disabled)
+func writeGitInfo(info *strings.Builder, status gitStatus, newWorktreeHint string) {
+	if !status.isRepo {
+		return
+	}
+
+	info.WriteString("\nGit:\n")
+	if status.err != nil {
+		info.WriteString(fmt.Sprintf("  Error: %s\n", firstLine(status.err.Error())))
+		return
+	}
+
+	info.WriteString(fmt.Sprintf("  Branch: %s\n", status.headName()))
+	if status.upstream != "" {
+		info.WriteString(fmt.Sprintf("  Upstream: %s (%d ahead, %d behind)\n", status.upstream, status.ahead, status.behind))
+	} else {
+		info.WriteString("  Upstream: none\n")
+	}
+	if status.dirty > 0 {
+		info.WriteString(fmt.Sprintf("  Changes: %d files\n", status.dirty))
+	} else {
+		info.WriteString("  Changes: clean\n")
+	}
+	if status.stashes > 0 {
+		info.WriteString(fmt.Sprintf("  Stashes: %d\n", status.stashes))
+	}
+	if status.lastCommit != "" {
+		info.WriteString(fmt.Sprintf("  Last commit: %s %s (%s)\n", status.commit, status.lastCommit, status.lastWhen))
+	}
+	if len(status.worktrees) > 1 {
+		info.WriteString(withHint(fmt.Sprintf("  Worktrees: %d", len(status.worktrees)), newWorktreeHint) + "\n")
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hooks.go - before/after hooks around launches
+// Hooks run synchronously with captured output; a failing before hook aborts the launch
+
+// hookOutputLines is how much hook output is kept for the error display
+const hookOutputLines = 20
+
+// hookDir returns where an item's hooks run: its cwd, its project, or $HOME
+func hookDir(item launchItem) string {
+	if item.Cwd != "" {
+		return item.Cwd
+	}
+	if item.ProjectPath != "" {
+		return item.ProjectPath
+	}
+	return os.Getenv("HOME")
+}
+
+// collectHooks gathers one phase's hooks for a launch, in item order
+// A hook shared by several items (e.g. a project's before) runs only once per directory
+func collectHooks(items []launchItem, phase string) []hookStep {
+	var steps []hookStep
+	seen := make(map[hookStep]bool)
+
+	for _, item := range items {
+		hooks := item.Before
+		if phase == "after" {
+			hooks = item.After
+		}
+		for _, hook := range hooks {
+			step := hookStep{command: hook, dir: hookDir(item)}
+			if !seen[step] {
+				seen[step] = true
+				steps = append(steps, step)
+			}
+		}
+	}
+
+	return steps
+}
+
+// runHooks runs hook steps in order and stops at the first failure
+func runHooks(phase string, steps []hookStep) error {
+	for _, step := range steps {
+		cmd := exec.Command("sh", "-c", step.command)
+		cmd.Dir = step.dir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return &hookError{
+				phase:   phase,
+				command: step.command,
+				output:  tailLines(string(output), hookOutputLines),
+				err:     err,
+			}
+		}
+	}
+	return nil
+}
+
+// withHooks wraps a spawn command with the before/after hooks of the launched items
+// before hooks failing aborts the spawn; after hooks only run when the spawn succeeded,
+// and their failure is reported beside the result (the panes exist either way)
+func withHooks(items []launchItem, spawn tea.Cmd) tea.Cmd {
+	before := collectHooks(items, "before")
+	after := collectHooks(items, "after")
+	if len(before) == 0 && len(after) == 0 {
+		return spawn
+	}
+
+	return func() tea.Msg {
+		if err := runHooks("before", before); err != nil {
+			return spawnCompleteMsg{err: err}
+		}
+
+		msg := spawn()
+		if done, ok := msg.(spawnCompleteMsg); ok && done.err == nil {
+			done.hookErr = runHooks("after", after)
+			return done
+		}
+		return msg
+	}
+}
+
+// directHooks runs before hooks for a direct-mode launch while the TUI is still up,
+// so a failure is shown instead of quitting
+func directHooks(item launchItem) tea.Cmd {
+	return func() tea.Msg {
+		if err := runHooks("before", collectHooks([]launchItem{item}, "before")); err != nil {
+			return spawnCompleteMsg{err: err}
+		}
+		return directRunMsg{item: item}
+	}
+}
+
+// tailLines keeps the last n lines of s
+func tailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
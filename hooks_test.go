@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCollectHooks(t *testing.T) {
+	t.Setenv("HOME", "/home/me")
+	project := launchItem{ProjectPath: "/src/app", Before: []string{"make deps"}, After: []string{"notify done"}}
+
+	tests := []struct {
+		name  string
+		items []launchItem
+		phase string
+		want  []hookStep
+	}{
+		{"no hooks", []launchItem{{Name: "htop"}}, "before", nil},
+		{"before hooks in item order", []launchItem{
+			{Cwd: "/a", Before: []string{"one", "two"}},
+			{Cwd: "/b", Before: []string{"three"}},
+		}, "before", []hookStep{{"one", "/a"}, {"two", "/a"}, {"three", "/b"}}},
+		{"after phase reads after hooks", []launchItem{project}, "after", []hookStep{{"notify done", "/src/app"}}},
+		{"shared project hook runs once", []launchItem{project, project}, "before", []hookStep{{"make deps", "/src/app"}}},
+		{"same hook in another directory runs again", []launchItem{
+			{Cwd: "/a", Before: []string{"make"}},
+			{Cwd: "/b", Before: []string{"make"}},
+		}, "before", []hookStep{{"make", "/a"}, {"make", "/b"}}},
+		{"cwd wins over the project", []launchItem{{Cwd: "/a", ProjectPath: "/src/app", Before: []string{"x"}}}, "before", []hookStep{{"x", "/a"}}},
+		{"no directory runs in home", []launchItem{{Before: []string{"x"}}}, "before", []hookStep{{"x", "/home/me"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := collectHooks(tt.items, tt.phase); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("collectHooks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunHooks(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	var last []string // What hookOutputLines keeps of seq 1 30
+	for i := 31 - hookOutputLines; i <= 30; i++ {
+		last = append(last, strconv.Itoa(i))
+	}
+
+	tests := []struct {
+		name       string
+		steps      []hookStep
+		wantErr    string // "" = success
+		wantOutput string
+		wantMarker bool
+	}{
+		{"no steps", nil, "", "", false},
+		{"runs in the step's directory", []hookStep{{"touch ran", dir}}, "", "", true},
+		{"stops at the first failure", []hookStep{{"echo oops; exit 3", dir}, {"touch ran", dir}}, `before hook "echo oops; exit 3" failed: exit status 3`, "oops", false},
+		{"keeps the tail of the output", []hookStep{{"seq 1 30; exit 1", dir}}, "failed", strings.Join(last, "\n"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(marker)
+			err := runHooks("before", tt.steps)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else {
+				var hookErr *hookError
+				if !errors.As(err, &hookErr) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want a hook error containing %q", err, tt.wantErr)
+				}
+				if hookErr.output != tt.wantOutput {
+					t.Errorf("output = %q, want %q", hookErr.output, tt.wantOutput)
+				}
+			}
+			if _, statErr := os.Stat(marker); (statErr == nil) != tt.wantMarker {
+				t.Errorf("marker exists = %v, want %v", statErr == nil, tt.wantMarker)
+			}
+		})
+	}
+}
+
+func TestWithHooks(t *testing.T) {
+	dir := t.TempDir()
+	spawnErr := errors.New("no tmux")
+
+	tests := []struct {
+		name        string
+		before      string
+		after       string
+		spawnErr    error
+		wantSpawn   bool
+		wantErr     string // "" = success
+		wantHookErr string // "" = after hooks passed (or didn't run)
+		wantAfter   bool
+	}{
+		{"hooks pass", "true", "touch after", nil, true, "", "", true},
+		{"before hook aborts the spawn", "exit 1", "touch after", nil, false, "before hook", "", false},
+		{"failed spawn skips after hooks", "true", "touch after", spawnErr, true, "no tmux", "", false},
+		{"after hook failure leaves the launch a success", "true", "touch after; exit 2", nil, true, "", "after hook", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(filepath.Join(dir, "after"))
+			item := launchItem{Cwd: dir, Before: []string{tt.before}, After: []string{tt.after}}
+			spawned := false
+			spawn := func() tea.Msg {
+				spawned = true
+				return spawnCompleteMsg{err: tt.spawnErr}
+			}
+
+			msg := withHooks([]launchItem{item}, spawn)().(spawnCompleteMsg)
+			if spawned != tt.wantSpawn {
+				t.Errorf("spawned = %v, want %v", spawned, tt.wantSpawn)
+			}
+			if tt.wantErr == "" && msg.err != nil {
+				t.Errorf("unexpected error: %v", msg.err)
+			}
+			if tt.wantErr != "" && (msg.err == nil || !strings.Contains(msg.err.Error(), tt.wantErr)) {
+				t.Errorf("error = %v, want one containing %q", msg.err, tt.wantErr)
+			}
+			if (msg.hookErr == nil) != (tt.wantHookErr == "") || (msg.hookErr != nil && !strings.Contains(msg.hookErr.Error(), tt.wantHookErr)) {
+				t.Errorf("hook error = %v, want %q", msg.hookErr, tt.wantHookErr)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "after")); (err == nil) != tt.wantAfter {
+				t.Errorf("after hook ran = %v, want %v", err == nil, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"", 3, ""},
+		{"a\nb\n", 3, "a\nb"},
+		{"a\nb\nc\nd\n", 2, "c\nd"},
+	}
+	for _, tt := range tests {
+		if got := tailLines(tt.s, tt.n); got != tt.want {
+			t.Errorf("tailLines(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestFinishLaunchAfterHookFailure(t *testing.T) {
+	var m model
+	item := launchItem{Name: "server"}
+	id := m.beginLaunch("dev", []launchItem{item}, nil)
+
+	m.finishLaunch(spawnCompleteMsg{
+		launchID:  id,
+		paneIDs:   []string{"%1"},
+		paneItems: []launchItem{item},
+		hookErr:   errors.New(`after hook "notify" failed: exit status 1`),
+	})
+
+	if m.launch.visible || m.launch.entries[0].phase != phaseDone {
+		t.Errorf("launch = %+v, want it done and hidden", m.launch)
+	}
+	if !m.toastIsError || !strings.Contains(m.toastText, "Launched, but after hook") {
+		t.Errorf("toast = %q (error %v), want the hook warning", m.toastText, m.toastIsError)
+	}
+	if len(m.errorLog) != 1 || !strings.Contains(m.errorLog[0].text, "notify") {
+		t.Errorf("error log = %+v, want the hook failure", m.errorLog)
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// icons.go - Icon sets
+// Emoji widths differ between terminals, which breaks tree alignment and
+// truncation. The icon set is picked from the terminal and locale, and can be
+// forced with icons: in the config
+
+// iconSets are the glyphs of each icon style
+var iconSets = map[iconStyle]iconSet{
+	iconsEmoji: {
+		style:       iconsEmoji,
+		expanded:    emojiExpanded,
+		collapsed:   emojiCollapsed,
+		selected:    emojiSelected,
+		unselected:  emojiUnselected,
+		running:     emojiRunning,
+		stopped:     emojiStopped,
+		crashed:     emojiCrashed,
+		waiting:     emojiWaiting,
+		treeBranch:  "├─",
+		treeLast:    "└─",
+		treePipe:    "│",
+		branch:      "⎇",
+		ahead:       "↑",
+		behind:      "↓",
+		dirty:       "●",
+		stash:       "⚑",
+		clean:       "✓",
+		scrollTrack: "│",
+		scrollThumb: "┃",
+		border:      lipgloss.RoundedBorder(),
+	},
+	iconsNerdFont: {
+		style:       iconsNerdFont,
+		expanded:    "\uf078", // nf-fa-chevron_down
+		collapsed:   "\uf054", // nf-fa-chevron_right
+		selected:    "\uf14a", // nf-fa-check_square
+		unselected:  "\uf096", // nf-fa-square_o
+		running:     "\uf111", // nf-fa-circle
+		stopped:     "\uf10c", // nf-fa-circle_o
+		crashed:     "\uf071", // nf-fa-warning
+		waiting:     "\uf252", // nf-fa-hourglass_half
+		treeBranch:  "├─",
+		treeLast:    "└─",
+		treePipe:    "│",
+		branch:      "\ue725", // nf-dev-git_branch
+		ahead:       "↑",
+		behind:      "↓",
+		dirty:       "●",
+		stash:       "\uf01c", // nf-fa-inbox
+		clean:       "\uf00c", // nf-fa-check
+		scrollTrack: "│",
+		scrollThumb: "┃",
+		border:      lipgloss.RoundedBorder(),
+		items: map[string]string{
+			emojiProject:    "\uf487",     // nf-oct-package
+			emojiFolder:     "\uf07b",     // nf-fa-folder
+			emojiCommand:    "\uf0e7",     // nf-fa-bolt
+			emojiProfile:    "\uf0ad",     // nf-fa-wrench
+			emojiTUITool:    "\uf120",     // nf-fa-terminal
+			emojiAI:         "\U000f06a9", // nf-md-robot
+			emojiScript:     "\uf15c",     // nf-fa-file_text
+			emojiFavorite:   "\uf005",     // nf-fa-star
+			emojiMonitoring: "\uf080",     // nf-fa-bar_chart
+			emojiGit:        "\ue702",     // nf-dev-git
+			emojiDatabase:   "\uf1c0",     // nf-fa-database
+			emojiServer:     "\uf233",     // nf-fa-server
+			emojiWorktree:   "\ue725",     // nf-dev-git_branch
+		},
+	},
+	iconsASCII: {
+		style:       iconsASCII,
+		expanded:    "-",
+		collapsed:   "+",
+		selected:    "[x]",
+		unselected:  "[ ]",
+		running:     "(run)",
+		stopped:     "(exit)",
+		crashed:     "(!)",
+		waiting:     "(...)",
+		treeBranch:  "|-",
+		treeLast:    "`-",
+		treePipe:    "|",
+		branch:      "git:",
+		ahead:       "+",
+		behind:      "-",
+		dirty:       "*",
+		stash:       "$",
+		clean:       "ok",
+		scrollTrack: "|",
+		scrollThumb: "#",
+		border:      lipgloss.ASCIIBorder(),
+		dropItems:   true,
+	},
+}
+
+// parseIconStyle parses the icons: config value ("" and "auto" mean detect)
+func parseIconStyle(s string) (iconStyle, bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "auto":
+		return iconsEmoji, false, nil
+	case "emoji":
+		return iconsEmoji, true, nil
+	case "nerd-font", "nerdfont", "nerd":
+		return iconsNerdFont, true, nil
+	case "ascii", "plain":
+		return iconsASCII, true, nil
+	default:
+		return iconsEmoji, false, fmt.Errorf("icons: unknown icon set %q (auto, emoji, nerd-font or ascii)", s)
+	}
+}
+
+// autoIconStyle picks the icon style for a terminal
+// Nerd Fonts can't be detected, so they are only used when configured
+func autoIconStyle(t terminalType) iconStyle {
+	if t == terminalConsole || !utf8Locale() {
+		return iconsASCII
+	}
+	return iconsEmoji
+}
+
+// utf8Locale reports whether the locale allows UTF-8 output
+// An unset locale is assumed to be UTF-8, as most terminals are
+func utf8Locale() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		locale := os.Getenv(name)
+		if locale == "" {
+			continue
+		}
+		locale = strings.ToLower(locale)
+		return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+	}
+	return true
+}
+
+// loadIcons resolves the config's icons: setting for a terminal
+// An unknown value is reported and the detected set is used instead
+func loadIcons(config Config, t terminalType) (iconSet, []string) {
+	style, forced, err := parseIconStyle(config.Icons)
+	if !forced {
+		style = autoIconStyle(t)
+	}
+	if err != nil {
+		return iconSets[style], []string{err.Error()}
+	}
+	return iconSets[style], nil
+}
+
+// item returns the glyph for an item icon ("" when the set has none)
+func (s iconSet) item(icon string) string {
+	if glyph, ok := s.items[icon]; ok {
+		return glyph
+	}
+	if s.dropItems {
+		return ""
+	}
+	return icon
+}
+
+// applyIcons switches the borders and spinner to the active icon set
+func (m *model) applyIcons() {
+	m.theme.border = m.theme.border.Border(m.icons.border)
+	m.theme.activeBorder = m.theme.activeBorder.Border(m.icons.border)
+	if m.icons.style == iconsASCII {
+		m.spinner.Spinner = spinner.Line
+	} else {
+		m.spinner.Spinner = spinner.Dot
+	}
+}
@@ -0,0 +1,609 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// import.go - Importing tmuxinator and tmuxp project files
+// A tmuxinator/tmuxp session becomes a project rooted at its root/start_directory,
+// and its window a profile. A profile is one tmux window, so files with several
+// windows are refused rather than split into profiles launched apart. Commands that run
+// before every pane (pre_window, shell_command_before) and environment variables
+// are prepended to the pane commands; session-level pre/before_script become the
+// project's before hooks. Anything else is reported as a warning
+
+// tmuxLayouts are the tmux preset layouts profiles can use
+var tmuxLayouts = []string{"main-vertical", "main-horizontal", "tiled", "even-horizontal", "even-vertical"}
+
+// tmuxinatorHooks are session-level tmuxinator commands that run before the session starts
+var tmuxinatorHooks = []string{"pre", "on_project_start", "on_project_first_start"}
+
+// importer converts one file, collecting warnings as it goes
+type importer struct {
+	file     string
+	warnings []string
+}
+
+// warn records something in the file that couldn't be imported
+func (im *importer) warn(format string, args ...any) {
+	im.warnings = append(im.warnings, filepath.Base(im.file)+": "+fmt.Sprintf(format, args...))
+}
+
+// importFile converts a tmuxinator or tmuxp file (YAML or JSON) into a project
+func importFile(path string) (ProjectConfig, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ProjectConfig{}, nil, err
+	}
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return ProjectConfig{}, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	doc, ok := asMap(raw)
+	if !ok {
+		return ProjectConfig{}, nil, fmt.Errorf("%s: not a tmuxinator or tmuxp file", path)
+	}
+
+	im := &importer{file: path}
+	var convert func(map[string]any) ProjectConfig
+	var windows any
+	switch {
+	case doc["session_name"] != nil:
+		convert, windows = im.tmuxp, doc["windows"]
+	case doc["windows"] != nil || doc["tabs"] != nil:
+		convert, windows = im.tmuxinator, firstValue(doc, "windows", "tabs")
+	default:
+		return ProjectConfig{}, nil, fmt.Errorf("%s: not a tmuxinator or tmuxp file (no session_name or windows)", path)
+	}
+	if list, _ := windows.([]any); len(list) > 1 {
+		return ProjectConfig{}, nil, fmt.Errorf("%s: %d windows, but a profile runs in a single window (split the file, one window per file)", path, len(list))
+	}
+	project := convert(doc)
+	if project.Name == "" {
+		project.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return project, im.warnings, nil
+}
+
+// tmuxinator converts a tmuxinator project
+func (im *importer) tmuxinator(doc map[string]any) ProjectConfig {
+	project := ProjectConfig{
+		Name: firstString(doc, "name", "project_name"),
+		Path: im.expandEnv(firstString(doc, "root", "project_root")),
+	}
+	for _, key := range tmuxinatorHooks {
+		project.Before = append(project.Before, im.commands(doc[key], key)...)
+	}
+	prefix := im.commands(firstValue(doc, "pre_window", "pre_tab", "rbenv", "rvm"), "pre_window")
+
+	handled := []string{"name", "project_name", "root", "project_root", "windows", "tabs", "pre_window", "pre_tab", "rbenv", "rvm"}
+	im.warnUnknown(doc, append(handled, tmuxinatorHooks...), "")
+
+	windows, _ := firstValue(doc, "windows", "tabs").([]any)
+	for i, w := range windows {
+		entry, ok := asMap(w)
+		if !ok || len(entry) != 1 {
+			im.warn("window %d: expected `- name: commands`, skipped", i+1)
+			continue
+		}
+		for name, value := range entry {
+			project.Profiles = append(project.Profiles, im.tmuxinatorWindow(name, value, project.Path, prefix))
+		}
+	}
+	return project
+}
+
+// tmuxinatorWindow converts one window: a command, a list of commands, or a map with panes
+func (im *importer) tmuxinatorWindow(name string, value any, root string, prefix []string) ProfileConfig {
+	profile := ProfileConfig{Name: name}
+	where := fmt.Sprintf("window %q", name)
+
+	settings, ok := asMap(value)
+	if !ok {
+		// `- server: rails s` (or a list run in one pane, or nothing)
+		profile.Panes = []paneConfig{{Command: joinCommands(prefix, im.commands(value, where)), Cwd: root}}
+		return profile
+	}
+
+	profile.Layout = im.layout(settings["layout"], where)
+	cwd := root // Panes start in the launcher's directory without a cwd
+	if dir, ok := scalarString(settings["root"]); ok {
+		cwd = joinRoot(root, im.expandEnv(dir))
+	}
+	prefix = append(append([]string{}, prefix...), im.commands(settings["pre"], where+" pre")...)
+	im.warnUnknown(settings, []string{"layout", "root", "pre", "panes"}, where)
+
+	panes, _ := settings["panes"].([]any)
+	if settings["panes"] == nil {
+		panes = []any{nil}
+	}
+	for i, p := range panes {
+		pane := paneConfig{Cwd: cwd}
+		if named, ok := asMap(p); ok && len(named) == 1 {
+			// - pane_name: [commands]
+			for paneName, commands := range named {
+				pane.Name = paneName
+				pane.Command = joinCommands(prefix, im.commands(commands, fmt.Sprintf("%s pane %q", where, paneName)))
+			}
+		} else {
+			pane.Command = joinCommands(prefix, im.commands(p, fmt.Sprintf("%s pane %d", where, i+1)))
+		}
+		profile.Panes = append(profile.Panes, pane)
+	}
+	return profile
+}
+
+// tmuxp converts a tmuxp session
+func (im *importer) tmuxp(doc map[string]any) ProjectConfig {
+	project := ProjectConfig{Path: im.expandEnv(firstString(doc, "start_directory"))}
+	project.Name, _ = scalarString(doc["session_name"])
+	if script, ok := scalarString(doc["before_script"]); ok {
+		project.Before = []string{script}
+	}
+	prefix := append(im.environment(doc["environment"], ""), im.commands(doc["shell_command_before"], "shell_command_before")...)
+	im.warnUnknown(doc, []string{"session_name", "start_directory", "before_script", "environment", "shell_command_before", "windows"}, "")
+
+	windows, _ := doc["windows"].([]any)
+	for i, w := range windows {
+		window, ok := asMap(w)
+		if !ok {
+			im.warn("window %d: expected a mapping, skipped", i+1)
+			continue
+		}
+		project.Profiles = append(project.Profiles, im.tmuxpWindow(window, i, project.Path, prefix))
+	}
+	return project
+}
+
+// tmuxpWindow converts one tmuxp window and its panes
+func (im *importer) tmuxpWindow(window map[string]any, index int, root string, prefix []string) ProfileConfig {
+	name, _ := scalarString(window["window_name"])
+	if name == "" {
+		name = fmt.Sprintf("window-%d", index+1)
+	}
+	where := fmt.Sprintf("window %q", name)
+	profile := ProfileConfig{Name: name, Layout: im.layout(window["layout"], where)}
+
+	cwd := root
+	if dir, ok := scalarString(window["start_directory"]); ok {
+		cwd = joinRoot(root, im.expandEnv(dir))
+	}
+	prefix = append(append(append([]string{}, prefix...), im.environment(window["environment"], where)...),
+		im.commands(window["shell_command_before"], where+" shell_command_before")...)
+	im.warnUnknown(window, []string{"window_name", "layout", "start_directory", "environment", "shell_command_before", "panes", "focus"}, where)
+
+	panes, _ := window["panes"].([]any)
+	if window["panes"] == nil {
+		panes = []any{nil}
+	}
+	for i, p := range panes {
+		paneWhere := fmt.Sprintf("%s pane %d", where, i+1)
+		pane := paneConfig{Cwd: cwd}
+		settings, ok := asMap(p)
+		if !ok {
+			// - "vim", or blank/pane/null for an empty pane
+			if s, _ := scalarString(p); s != "blank" && s != "pane" {
+				pane.Command = joinCommands(prefix, im.commands(p, paneWhere))
+			} else {
+				pane.Command = joinCommands(prefix, nil)
+			}
+			profile.Panes = append(profile.Panes, pane)
+			continue
+		}
+
+		if dir, ok := scalarString(settings["start_directory"]); ok {
+			pane.Cwd = joinRoot(cwd, im.expandEnv(dir))
+		}
+		panePrefix := append(append([]string{}, prefix...), im.environment(settings["environment"], paneWhere)...)
+		pane.Command = joinCommands(panePrefix, im.commands(settings["shell_command"], paneWhere))
+		im.warnUnknown(settings, []string{"shell_command", "start_directory", "environment", "focus"}, paneWhere)
+		profile.Panes = append(profile.Panes, pane)
+	}
+	return profile
+}
+
+// commands reads a command or list of commands (tmuxp also allows {cmd: ...} entries)
+func (im *importer) commands(value any, where string) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []any:
+		var commands []string
+		for _, entry := range v {
+			commands = append(commands, im.commands(entry, where)...)
+		}
+		return commands
+	}
+	if m, ok := asMap(value); ok {
+		if cmd, ok := scalarString(m["cmd"]); ok {
+			im.warnUnknown(m, []string{"cmd"}, where)
+			return []string{cmd}
+		}
+		im.warn("%s: unsupported command %v, skipped", where, value)
+		return nil
+	}
+	if s, ok := scalarString(value); ok && s != "" {
+		return []string{s}
+	}
+	return nil
+}
+
+// environment turns a tmuxp environment: map into export commands, in key order
+func (im *importer) environment(value any, where string) []string {
+	env, ok := asMap(value)
+	if !ok {
+		if value != nil {
+			im.warn("%s environment: expected a mapping, skipped", strings.TrimSpace(where))
+		}
+		return nil
+	}
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var exports []string
+	for _, k := range keys {
+		v, _ := scalarString(env[k])
+		exports = append(exports, "export "+k+"="+shellQuote(v))
+	}
+	return exports
+}
+
+// layout returns a window's layout if profiles support it
+// Custom layout strings (from list-windows) can't be mapped
+func (im *importer) layout(value any, where string) string {
+	layout, ok := scalarString(value)
+	if !ok || layout == "" {
+		return ""
+	}
+	for _, known := range tmuxLayouts {
+		if layout == known {
+			return layout
+		}
+	}
+	im.warn("%s: custom layout %q replaced with tiled", where, layout)
+	return "tiled"
+}
+
+// warnUnknown warns about every key of m that isn't handled
+func (im *importer) warnUnknown(m map[string]any, handled []string, where string) {
+	var unknown []string
+	for k := range m {
+		known := false
+		for _, h := range handled {
+			known = known || k == h
+		}
+		if !known {
+			unknown = append(unknown, k)
+		}
+	}
+	sort.Strings(unknown)
+	for _, k := range unknown {
+		if where == "" {
+			im.warn("%s is not supported, ignored", k)
+		} else {
+			im.warn("%s: %s is not supported, ignored", where, k)
+		}
+	}
+}
+
+// joinCommands runs prefix, then commands, in one pane command line
+func joinCommands(prefix, commands []string) string {
+	return strings.Join(append(append([]string{}, prefix...), commands...), "; ")
+}
+
+// expandEnv expands $VAR and ${VAR} in a directory, as the shell would have
+// Unset variables are left in place with a warning
+func (im *importer) expandEnv(dir string) string {
+	return os.Expand(dir, func(name string) string {
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+		im.warn("%s: $%s is not set, left as is", dir, name)
+		return "${" + name + "}"
+	})
+}
+
+// joinRoot resolves a window or pane directory against the session root
+// ~ and unset variables (see expandEnv) are kept as they are
+func joinRoot(root, dir string) string {
+	if root == "" || filepath.IsAbs(dir) || strings.HasPrefix(dir, "~") || strings.HasPrefix(dir, "$") {
+		return dir
+	}
+	return filepath.Join(root, dir)
+}
+
+// asMap returns value as a string-keyed map (YAML keys like `1:` become "1")
+func asMap(value any) (map[string]any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		return v, true
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = val
+		}
+		return m, true
+	}
+	return nil, false
+}
+
+// scalarString returns a scalar as a string (numbers and booleans too)
+func scalarString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case int, int64, float64, bool:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
+
+// firstValue returns the value of the first key present in m
+func firstValue(m map[string]any, keys ...string) any {
+	for _, k := range keys {
+		if v, ok := m[k]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// firstString returns the first key of m holding a scalar, as a string
+func firstString(m map[string]any, keys ...string) string {
+	s, _ := scalarString(firstValue(m, keys...))
+	return s
+}
+
+// configFilePath is the config file the launcher reads
+func configFilePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "tui-launcher", "config.yaml"), nil
+}
+
+// importFiles converts files into projects named apart from each other and the config's
+// Files that can't be read are reported with the warnings; it fails only if none could
+func importFiles(paths []string, existing []ProjectConfig) ([]ProjectConfig, []string, error) {
+	taken := make(map[string]bool)
+	for _, p := range existing {
+		taken[p.Name] = true
+	}
+
+	var projects []ProjectConfig
+	var warnings []string
+	for _, path := range paths {
+		project, fileWarnings, err := importFile(path)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		warnings = append(warnings, fileWarnings...)
+
+		name := project.Name
+		for n := 2; taken[name]; n++ {
+			name = fmt.Sprintf("%s-%d", project.Name, n)
+		}
+		if name != project.Name {
+			warnings = append(warnings, fmt.Sprintf("%s: a project %q exists, imported as %q", filepath.Base(path), project.Name, name))
+			project.Name = name
+		}
+		taken[name] = true
+		projects = append(projects, project)
+	}
+	if len(projects) == 0 {
+		return nil, warnings, fmt.Errorf("nothing imported")
+	}
+	return projects, warnings, nil
+}
+
+// appendProjects adds projects to the projects: list of the config file
+// The file is edited as a YAML tree, which keeps comments; the previous
+// version is kept as config.yaml.bak
+func appendProjects(configPath string, projects []ProjectConfig) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: %w", configPath, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: expected a mapping at the top level", configPath)
+	}
+
+	var list *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "projects" {
+			list = root.Content[i+1]
+		}
+	}
+	if list == nil {
+		list = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "projects"}, list)
+	}
+	if list.Kind == yaml.ScalarNode && list.Tag == "!!null" {
+		*list = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	}
+	if list.Kind != yaml.SequenceNode {
+		return fmt.Errorf("%s: projects: is not a list", configPath)
+	}
+	for _, project := range projects {
+		var node yaml.Node
+		if err := node.Encode(project); err != nil {
+			return err
+		}
+		list.Content = append(list.Content, &node)
+	}
+
+	out, err := marshalYAML(&doc)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return err
+	}
+	if len(data) > 0 {
+		if err := os.WriteFile(configPath+".bak", data, 0644); err != nil {
+			return err
+		}
+	}
+	tmp := configPath + ".tmp"
+	if err := os.WriteFile(tmp, out, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, configPath)
+}
+
+// marshalYAML encodes v with the two-space indent of the example config
+func marshalYAML(v any) ([]byte, error) {
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), encoder.Close()
+}
+
+// importIntoConfig imports files and appends them to the config file
+func importIntoConfig(paths []string) ([]ProjectConfig, []string, error) {
+	configPath, err := configFilePath()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Names are checked against the file itself (includes don't hold projects)
+	var existing Config
+	if data, err := os.ReadFile(configPath); err == nil {
+		if err := yaml.Unmarshal(data, &existing); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", configPath, err)
+		}
+	}
+
+	projects, warnings, err := importFiles(paths, existing.Projects)
+	if err != nil {
+		return nil, warnings, err
+	}
+	return projects, warnings, appendProjects(configPath, projects)
+}
+
+// runImport handles "tui-launcher import [--print] <file>..."
+func runImport(args []string) error {
+	printOnly := len(args) > 0 && args[0] == "--print"
+	if printOnly {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("usage: tui-launcher import [--print] <tmuxinator or tmuxp file>...")
+	}
+
+	var projects []ProjectConfig
+	var warnings []string
+	var err error
+	if printOnly {
+		projects, warnings, err = importFiles(args, nil)
+	} else {
+		projects, warnings, err = importIntoConfig(args)
+	}
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, "warning: "+warning)
+	}
+	if err != nil {
+		return err
+	}
+
+	if printOnly {
+		out, err := marshalYAML(map[string][]ProjectConfig{"projects": projects})
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(out))
+		return nil
+	}
+	configPath, _ := configFilePath()
+	fmt.Printf("Imported %s into %s\n", importSummary(projects), configPath)
+	return nil
+}
+
+// importSummary describes imported projects ("2 projects (5 profiles)")
+func importSummary(projects []ProjectConfig) string {
+	profiles := 0
+	for _, p := range projects {
+		profiles += len(p.Profiles)
+	}
+	return fmt.Sprintf("%d project(s) (%d profile(s))", len(projects), profiles)
+}
+
+// importConfig imports files in the background for the import prompt
+func importConfig(paths []string) tea.Cmd {
+	return func() tea.Msg {
+		projects, warnings, err := importIntoConfig(paths)
+		return importDoneMsg{projects: projects, warnings: warnings, err: err}
+	}
+}
+
+// openImportPrompt asks for the files to import
+func (m *model) openImportPrompt() {
+	m.showImportPrompt = true
+	m.importPath = ""
+}
+
+// updateImportPrompt handles keys while the import prompt is open
+func (m model) updateImportPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var action promptAction
+	m.importPath, action = editPrompt(m.importPath, msg)
+	switch action {
+	case promptQuit:
+		return m, tea.Quit
+
+	case promptCancel:
+		m.showImportPrompt = false
+
+	case promptSubmit:
+		pattern := expandPath(strings.TrimSpace(m.importPath))
+		if pattern == "" {
+			return m, nil
+		}
+		m.showImportPrompt = false
+		paths, err := filepath.Glob(pattern)
+		if err != nil || len(paths) == 0 {
+			return m, m.showToast("✗ No files match "+pattern, true)
+		}
+		return m, tea.Batch(importConfig(paths), m.showToast(fmt.Sprintf("Importing %d file(s)…", len(paths)), false))
+	}
+	return m, nil
+}
+
+// viewImportPrompt renders the import prompt centered over the screen
+func (m model) viewImportPrompt() string {
+	return m.viewPromptBox(
+		m.theme.heading.Render("Import tmuxinator / tmuxp files"),
+		"",
+		promptField("File: ", m.importPath),
+		"",
+		"Globs work too, e.g. ~/.config/tmuxinator/*.yml",
+		"Each file becomes a project, its window a profile",
+		"",
+		"Enter: import  Esc: cancel",
+	)
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportFile(t *testing.T) {
+	tests := []struct {
+		name         string
+		file         string
+		content      string
+		want         ProjectConfig
+		wantWarnings []string
+		wantErr      string
+	}{
+		{
+			name: "tmuxinator list window",
+			file: "app.yml",
+			content: `name: app
+root: ~/src/app
+pre_window: nvm use
+windows:
+  - logs:
+      - cd log
+      - tail -f dev.log
+`,
+			want: ProjectConfig{Name: "app", Path: "~/src/app", Profiles: []ProfileConfig{
+				{Name: "logs", Panes: []paneConfig{{Command: "nvm use; cd log; tail -f dev.log", Cwd: "~/src/app"}}},
+			}},
+		},
+		{
+			name:    "tmuxinator empty window",
+			file:    "shell.yml",
+			content: "tabs:\n  - shell:\n",
+			want:    ProjectConfig{Name: "shell", Profiles: []ProfileConfig{{Name: "shell", Panes: []paneConfig{{}}}}},
+		},
+		{
+			name:    "tmuxinator with several windows",
+			file:    "multi.yml",
+			content: "name: multi\nwindows:\n  - editor: vim\n  - shell:\n",
+			wantErr: "multi.yml: 2 windows, but a profile runs in a single window",
+		},
+		{
+			name:    "tmuxp with several windows",
+			file:    "multi.yaml",
+			content: "session_name: multi\nwindows:\n  - panes: [vim]\n  - panes: [top]\n  - panes: [htop]\n",
+			wantErr: "multi.yaml: 3 windows, but a profile runs in a single window",
+		},
+		{
+			name: "tmuxinator window with panes, root and pre",
+			file: "web.yml",
+			content: `name: web
+root: /srv/web
+pre: docker compose up -d
+windows:
+  - main:
+      layout: main-vertical
+      root: frontend
+      pre: source .env
+      panes:
+        - npm run dev
+        - tests:
+            - npm test -- --watch
+        -
+`,
+			want: ProjectConfig{Name: "web", Path: "/srv/web", Before: []string{"docker compose up -d"}, Profiles: []ProfileConfig{
+				{Name: "main", Layout: "main-vertical", Panes: []paneConfig{
+					{Command: "source .env; npm run dev", Cwd: "/srv/web/frontend"},
+					{Name: "tests", Command: "source .env; npm test -- --watch", Cwd: "/srv/web/frontend"},
+					{Command: "source .env", Cwd: "/srv/web/frontend"},
+				}},
+			}},
+		},
+		{
+			name: "tmuxinator warnings",
+			file: "warn.yml",
+			content: `root: /p
+startup_window: main
+tmux_options: -f ~/.tmux.alt.conf
+windows:
+  - main:
+      layout: 5e1b,213x50,0,0{106x50,0,0,1,106x50,107,0,2}
+      synchronize: true
+      panes:
+        - ls
+`,
+			want: ProjectConfig{Name: "warn", Path: "/p", Profiles: []ProfileConfig{
+				{Name: "main", Layout: "tiled", Panes: []paneConfig{{Command: "ls", Cwd: "/p"}}},
+			}},
+			wantWarnings: []string{
+				"warn.yml: startup_window is not supported, ignored",
+				"warn.yml: tmux_options is not supported, ignored",
+				`warn.yml: window "main": custom layout "5e1b,213x50,0,0{106x50,0,0,1,106x50,107,0,2}" replaced with tiled`,
+				`warn.yml: window "main": synchronize is not supported, ignored`,
+			},
+		},
+		{
+			name:         "tmuxinator malformed window",
+			file:         "odd.yml",
+			content:      "windows:\n  - not: a\n    single: window\n",
+			want:         ProjectConfig{Name: "odd"},
+			wantWarnings: []string{"odd.yml: window 1: expected `- name: commands`, skipped"},
+		},
+		{
+			name: "tmuxp yaml",
+			file: "api.yaml",
+			content: `session_name: api
+start_directory: /srv/api
+before_script: ./bootstrap.sh
+environment:
+  PORT: 8080
+  DEBUG: "it's on"
+shell_command_before:
+  - cmd: source venv/bin/activate
+windows:
+  - window_name: server
+    layout: even-horizontal
+    start_directory: cmd
+    panes:
+      - shell_command:
+          - go run .
+      - blank
+      - null
+      - start_directory: /tmp
+        environment:
+          MODE: test
+        shell_command: go test ./...
+        focus: true
+`,
+			want: ProjectConfig{Name: "api", Path: "/srv/api", Before: []string{"./bootstrap.sh"}, Profiles: []ProfileConfig{
+				{Name: "server", Layout: "even-horizontal", Panes: []paneConfig{
+					{Command: "export DEBUG='it'\\''s on'; export PORT=8080; source venv/bin/activate; go run .", Cwd: "/srv/api/cmd"},
+					{Command: "export DEBUG='it'\\''s on'; export PORT=8080; source venv/bin/activate", Cwd: "/srv/api/cmd"},
+					{Command: "export DEBUG='it'\\''s on'; export PORT=8080; source venv/bin/activate", Cwd: "/srv/api/cmd"},
+					{Command: "export DEBUG='it'\\''s on'; export PORT=8080; source venv/bin/activate; export MODE=test; go test ./...", Cwd: "/tmp"},
+				}},
+			}},
+		},
+		{
+			name:    "tmuxp json with an unnamed window",
+			file:    "j.json",
+			content: `{"session_name": "j", "options": {"mouse": "on"}, "windows": [{"panes": ["htop"], "options": {}}]}`,
+			want: ProjectConfig{Name: "j", Profiles: []ProfileConfig{
+				{Name: "window-1", Panes: []paneConfig{{Command: "htop"}}},
+			}},
+			wantWarnings: []string{
+				"j.json: options is not supported, ignored",
+				`j.json: window "window-1": options is not supported, ignored`,
+			},
+		},
+		{
+			name:    "tmuxp json warnings",
+			file:    "e.json",
+			content: `{"session_name": "e", "windows": [{"window_name": "e", "environment": "bad", "panes": [{"shell_command": [{"cmd": "ls", "sleep_before": 1}, {"run": "x"}]}]}]}`,
+			want: ProjectConfig{Name: "e", Profiles: []ProfileConfig{
+				{Name: "e", Panes: []paneConfig{{Command: "ls"}}},
+			}},
+			wantWarnings: []string{
+				`e.json: window "e" environment: expected a mapping, skipped`,
+				`e.json: window "e" pane 1: sleep_before is not supported, ignored`,
+				`e.json: window "e" pane 1: unsupported command map[run:x], skipped`,
+			},
+		},
+		{
+			name:         "tmuxp window that isn't a mapping",
+			file:         "oops.yaml",
+			content:      "session_name: oops\nwindows: [oops]\n",
+			want:         ProjectConfig{Name: "oops"},
+			wantWarnings: []string{"oops.yaml: window 1: expected a mapping, skipped"},
+		},
+		{
+			name: "tmuxinator environment variables in directories",
+			file: "env.yml",
+			content: `root: $IMPORT_TEST_SRC/app
+windows:
+  - main:
+      root: ${IMPORT_TEST_OPT}/web
+      panes:
+        - ls
+`,
+			want: ProjectConfig{Name: "env", Path: "/srv/app", Profiles: []ProfileConfig{
+				{Name: "main", Panes: []paneConfig{{Command: "ls", Cwd: "/opt/web"}}},
+			}},
+		},
+		{
+			name: "tmuxp unset environment variable",
+			file: "unset.yaml",
+			content: `session_name: unset
+start_directory: $IMPORT_TEST_SRC
+windows:
+  - window_name: w
+    start_directory: $IMPORT_TEST_UNSET/x
+    panes: [ls]
+`,
+			want: ProjectConfig{Name: "unset", Path: "/srv", Profiles: []ProfileConfig{
+				{Name: "w", Panes: []paneConfig{{Command: "ls", Cwd: "${IMPORT_TEST_UNSET}/x"}}},
+			}},
+			wantWarnings: []string{"unset.yaml: $IMPORT_TEST_UNSET/x: $IMPORT_TEST_UNSET is not set, left as is"},
+		},
+		{
+			name:    "name defaults to the file name",
+			file:    "unnamed.yml",
+			content: "windows:\n  - w: top\n",
+			want:    ProjectConfig{Name: "unnamed", Profiles: []ProfileConfig{{Name: "w", Panes: []paneConfig{{Command: "top"}}}}},
+		},
+		{
+			name:    "malformed yaml",
+			file:    "bad.yml",
+			content: "windows: [unclosed\n",
+			wantErr: "bad.yml",
+		},
+		{
+			name:    "not a mapping",
+			file:    "list.yml",
+			content: "- a\n- b\n",
+			wantErr: "not a tmuxinator or tmuxp file",
+		},
+		{
+			name:    "neither tmuxinator nor tmuxp",
+			file:    "other.yml",
+			content: "name: x\nroot: /tmp\n",
+			wantErr: "no session_name or windows",
+		},
+	}
+
+	t.Setenv("IMPORT_TEST_SRC", "/srv")
+	t.Setenv("IMPORT_TEST_OPT", "/opt")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeFiles(t, map[string]string{tt.file: tt.content})
+			got, warnings, err := importFile(filepath.Join(dir, tt.file))
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("project = %+v\nwant      %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(warnings, tt.wantWarnings) {
+				t.Errorf("warnings = %q\nwant       %q", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestImportFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.yml":   "name: app\nwindows:\n  - w: top\n",
+		"b.yml":   "session_name: app\nwindows:\n  - panes: [top]\n",
+		"bad.yml": "name: x\n",
+	})
+	paths := []string{filepath.Join(dir, "a.yml"), filepath.Join(dir, "b.yml"), filepath.Join(dir, "bad.yml"), filepath.Join(dir, "missing.yml")}
+
+	projects, warnings, err := importFiles(paths, []ProjectConfig{{Name: "app"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range projects {
+		names = append(names, p.Name)
+	}
+	if want := []string{"app-2", "app-3"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+	for _, want := range []string{`a.yml: a project "app" exists, imported as "app-2"`, `b.yml: a project "app" exists, imported as "app-3"`, "no session_name or windows", "missing.yml"} {
+		found := false
+		for _, w := range warnings {
+			found = found || strings.Contains(w, want)
+		}
+		if !found {
+			t.Errorf("warnings %q lack %q", warnings, want)
+		}
+	}
+
+	if _, _, err := importFiles(paths[2:], nil); err == nil || err.Error() != "nothing imported" {
+		t.Errorf("importing only bad files: error = %v, want nothing imported", err)
+	}
+}
+
+func TestAppendProjects(t *testing.T) {
+	project := ProjectConfig{Name: "new", Profiles: []ProfileConfig{{Name: "p", Panes: []paneConfig{{Command: "top"}}}}}
+
+	tests := []struct {
+		name     string
+		existing string // "" = no file
+		want     []string
+		wantErr  string
+	}{
+		{"no config file", "", []string{"projects:\n  - name: new\n"}, ""},
+		{"no projects key", "# my tools\ntools: []\n", []string{"# my tools\n", "projects:\n  - name: new\n"}, ""},
+		{"empty projects key", "projects:\n", []string{"projects:\n  - name: new\n"}, ""},
+		{
+			"appends after existing projects",
+			"projects:\n  # keep me\n  - name: old # inline\n",
+			[]string{"# keep me", "name: old # inline", "  - name: new\n"},
+			"",
+		},
+		{"projects is not a list", "projects: nope\n", nil, "projects: is not a list"},
+		{"top level is not a mapping", "- a\n", nil, "expected a mapping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if tt.existing != "" {
+				if err := os.WriteFile(configPath, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := appendProjects(configPath, []ProjectConfig{project})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			data, err := os.ReadFile(configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("config lacks %q:\n%s", want, data)
+				}
+			}
+			backup, err := os.ReadFile(configPath + ".bak")
+			if tt.existing == "" && err == nil {
+				t.Error("backup written without a previous config")
+			}
+			if tt.existing != "" && string(backup) != tt.existing {
+				t.Errorf("backup = %q, want %q", backup, tt.existing)
+			}
+		})
+	}
+}
+
+func TestShellPath(t *testing.T) {
+	tests := []struct{ path, want string }{
+		{"~", "~"},
+		{"~/src/my app", "~/'src/my app'"},
+		{"/srv/it's", `'/srv/it'\''s'`},
+		{"~user/x", "'~user/x'"},
+	}
+	for _, tt := range tests {
+		if got := shellPath(tt.path); got != tt.want {
+			t.Errorf("shellPath(%q) = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// include.go - include: entries
+// An entry with include: is replaced by the entries of another YAML file (a
+// list in the same format as items:). With category: as well, the included
+// entries become that category's items. Includes may nest; a file that ends
+// up including itself is an error
+
+// resolveIncludes replaces the include: entries of every items:, ai: and commands: list
+// configPath is the config file, which relative includes start from
+func resolveIncludes(config *Config, configPath string) error {
+	stack := []string{filepath.Clean(configPath)}
+	dir := filepath.Dir(configPath)
+
+	var err error
+	for i := range config.Tools {
+		if config.Tools[i].Items, err = includeEntries(config.Tools[i].Items, dir, stack); err != nil {
+			return err
+		}
+	}
+	if config.AI, err = includeEntries(config.AI, dir, stack); err != nil {
+		return err
+	}
+	for i := range config.Scripts {
+		if config.Scripts[i].Items, err = includeEntries(config.Scripts[i].Items, dir, stack); err != nil {
+			return err
+		}
+	}
+	for i := range config.Projects {
+		if config.Projects[i].Commands, err = includeEntries(config.Projects[i].Commands, dir, stack); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// includeEntries resolves includes in entries read from a file in dir
+// stack holds the files being included, outermost first, to catch cycles
+func includeEntries(entries []CommandConfig, dir string, stack []string) ([]CommandConfig, error) {
+	var result []CommandConfig
+	for _, entry := range entries {
+		var err error
+		if entry.Items, err = includeEntries(entry.Items, dir, stack); err != nil {
+			return nil, err
+		}
+		if entry.Include == "" {
+			result = append(result, entry)
+			continue
+		}
+
+		path := expandPath(entry.Include)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		path = filepath.Clean(path)
+		for i, file := range stack {
+			if file == path {
+				cycle := append(append([]string{}, stack[i:]...), path)
+				return nil, fmt.Errorf("include cycle: %s", strings.Join(cycle, " → "))
+			}
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("include %s: %w", entry.Include, err)
+		}
+		var included []CommandConfig
+		if err := yaml.Unmarshal(data, &included); err != nil {
+			return nil, fmt.Errorf("include %s: %w", path, err)
+		}
+		included, err = includeEntries(included, filepath.Dir(path), append(stack[:len(stack):len(stack)], path))
+		if err != nil {
+			return nil, err
+		}
+
+		// Without a category the included entries take the include's place
+		if entry.Category == "" && entry.Name == "" {
+			result = append(result, included...)
+			continue
+		}
+		entry.Items = append(entry.Items, included...)
+		entry.Include = ""
+		result = append(result, entry)
+	}
+	return result, nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFiles creates files (relative path → content) under a temp dir and returns it
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// entryNames lists entry names depth-first, children as "parent/child"
+func entryNames(entries []CommandConfig, prefix string) []string {
+	var names []string
+	for _, e := range entries {
+		name := e.Name
+		if e.Category != "" {
+			name = e.Category
+		}
+		names = append(names, prefix+name)
+		names = append(names, entryNames(e.Items, prefix+name+"/")...)
+	}
+	return names
+}
+
+func TestIncludeEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		entries []CommandConfig
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "include takes the entry's place",
+			files:   map[string]string{"git.yaml": "- name: lazygit\n- name: tig\n"},
+			entries: []CommandConfig{{Name: "htop"}, {Include: "git.yaml"}, {Name: "btop"}},
+			want:    []string{"htop", "lazygit", "tig", "btop"},
+		},
+		{
+			name:    "include with a category fills it",
+			files:   map[string]string{"git.yaml": "- name: lazygit\n"},
+			entries: []CommandConfig{{Category: "Git", Include: "git.yaml", Items: []CommandConfig{{Name: "gh"}}}},
+			want:    []string{"Git", "Git/gh", "Git/lazygit"},
+		},
+		{
+			name: "nested includes resolve against the including file",
+			files: map[string]string{
+				"sub/a.yaml": "- name: a\n- include: b.yaml\n",
+				"sub/b.yaml": "- category: B\n  items:\n    - name: b\n",
+			},
+			entries: []CommandConfig{{Include: "sub/a.yaml"}},
+			want:    []string{"a", "B", "B/b"},
+		},
+		{
+			name:    "include inside a nested category",
+			files:   map[string]string{"x.yaml": "- name: x\n"},
+			entries: []CommandConfig{{Category: "Outer", Items: []CommandConfig{{Category: "Inner", Include: "x.yaml"}}}},
+			want:    []string{"Outer", "Outer/Inner", "Outer/Inner/x"},
+		},
+		{
+			name:    "same file included twice is not a cycle",
+			files:   map[string]string{"common.yaml": "- name: c\n"},
+			entries: []CommandConfig{{Category: "A", Include: "common.yaml"}, {Category: "B", Include: "common.yaml"}},
+			want:    []string{"A", "A/c", "B", "B/c"},
+		},
+		{
+			name:    "file including itself",
+			files:   map[string]string{"self.yaml": "- include: self.yaml\n"},
+			entries: []CommandConfig{{Include: "self.yaml"}},
+			wantErr: "include cycle",
+		},
+		{
+			name: "two files including each other",
+			files: map[string]string{
+				"a.yaml": "- name: a\n- include: b.yaml\n",
+				"b.yaml": "- category: B\n  include: a.yaml\n",
+			},
+			entries: []CommandConfig{{Include: "a.yaml"}},
+			wantErr: "include cycle",
+		},
+		{
+			name:    "including the config file",
+			files:   map[string]string{"back.yaml": "- include: config.yaml\n"},
+			entries: []CommandConfig{{Include: "back.yaml"}},
+			wantErr: "config.yaml → ",
+		},
+		{
+			name:    "missing file",
+			entries: []CommandConfig{{Include: "nope.yaml"}},
+			wantErr: "include nope.yaml",
+		},
+		{
+			name:    "malformed yaml",
+			files:   map[string]string{"bad.yaml": "- name: [unclosed\n"},
+			entries: []CommandConfig{{Include: "bad.yaml"}},
+			wantErr: "bad.yaml",
+		},
+		{
+			name:    "not a list",
+			files:   map[string]string{"map.yaml": "name: single\n"},
+			entries: []CommandConfig{{Include: "map.yaml"}},
+			wantErr: "map.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeFiles(t, tt.files)
+			stack := []string{filepath.Join(dir, "config.yaml")}
+			got, err := includeEntries(tt.entries, dir, stack)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if names := entryNames(got, ""); !reflect.DeepEqual(names, tt.want) {
+				t.Fatalf("entries = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveIncludesCoversEverySection(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"x.yaml": "- name: x\n"})
+	include := []CommandConfig{{Include: "x.yaml"}}
+	config := Config{
+		Tools:    []CategoryConfig{{Category: "T", Items: include}},
+		AI:       include,
+		Scripts:  []CategoryConfig{{Category: "S", Items: include}},
+		Projects: []ProjectConfig{{Name: "P", Commands: include}},
+	}
+
+	if err := resolveIncludes(&config, filepath.Join(dir, "config.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	for section, entries := range map[string][]CommandConfig{
+		"tools":    config.Tools[0].Items,
+		"ai":       config.AI,
+		"scripts":  config.Scripts[0].Items,
+		"projects": config.Projects[0].Commands,
+	} {
+		if names := entryNames(entries, ""); !reflect.DeepEqual(names, []string{"x"}) {
+			t.Errorf("%s: entries = %v, want [x]", section, names)
+		}
+	}
+}
@@ -93,6 +93,10 @@ echo -e "${BLUE}Usage:${NC}"
 echo -e "  tl                    ${BLUE}# Launch TUI${NC}"
 echo -e "  tui-launcher          ${BLUE}# Full command${NC}"
 echo ""
+echo -e "${BLUE}cd into projects on Enter:${NC}"
+echo -e "  eval \"\$(tui-launcher shell-init bash)\"   ${BLUE}# Add to ~/.bashrc (or: shell-init zsh)${NC}"
+echo -e "  tui-launcher shell-init fish | source    ${BLUE}# Add to ~/.config/fish/config.fish${NC}"
+echo ""
 echo -e "${BLUE}Keybindings:${NC}"
 echo -e "  ↑/↓ or j/k            ${BLUE}# Navigate${NC}"
 echo -e "  Space                 ${BLUE}# Expand category OR select command${NC}"
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// keys.go - Key bindings
+// Every action of the main view has a binding in keyMap; the keys: section of
+// the config rebinds or disables actions by name
+
+// keyAction names an action for the keys: config section
+// Actions with a title are offered in the command palette
+type keyAction struct {
+	name    string
+	title   string
+	binding func(k *keyMap) *key.Binding
+}
+
+// spawnDialogActions are the actions the spawn dialog responds to
+// Keys only have to be unique within the main view and within the dialog
+var spawnDialogActions = []string{"up", "down", "move_item_up", "move_item_down", "spawn_target", "launch", "dismiss", "quit"}
+
+// keyActions lists the configurable actions in help order
+var keyActions = []keyAction{
+	{"up", "", func(k *keyMap) *key.Binding { return &k.Up }},
+	{"down", "", func(k *keyMap) *key.Binding { return &k.Down }},
+	{"expand", "", func(k *keyMap) *key.Binding { return &k.Expand }},
+	{"collapse", "", func(k *keyMap) *key.Binding { return &k.Collapse }},
+	{"page_up", "", func(k *keyMap) *key.Binding { return &k.PageUp }},
+	{"page_down", "", func(k *keyMap) *key.Binding { return &k.PageDown }},
+	{"half_page_up", "", func(k *keyMap) *key.Binding { return &k.HalfPageUp }},
+	{"half_page_down", "", func(k *keyMap) *key.Binding { return &k.HalfPageDown }},
+	{"top", "Go to first item", func(k *keyMap) *key.Binding { return &k.Top }},
+	{"bottom", "Go to last item", func(k *keyMap) *key.Binding { return &k.Bottom }},
+	{"info_up", "", func(k *keyMap) *key.Binding { return &k.InfoUp }},
+	{"info_down", "", func(k *keyMap) *key.Binding { return &k.InfoDown }},
+	{"switch_pane", "Switch pane", func(k *keyMap) *key.Binding { return &k.SwitchPane }},
+	{"toggle_info", "Toggle info pane", func(k *keyMap) *key.Binding { return &k.ToggleInfo }},
+	{"select", "Expand / select current item", func(k *keyMap) *key.Binding { return &k.Select }},
+	{"launch", "Launch", func(k *keyMap) *key.Binding { return &k.Launch }},
+	{"clear_selection", "Clear selection", func(k *keyMap) *key.Binding { return &k.ClearSelection }},
+	{"toggle_tmux", "Toggle tmux mode", func(k *keyMap) *key.Binding { return &k.ToggleTmux }},
+	{"spawn_target", "Cycle spawn target", func(k *keyMap) *key.Binding { return &k.SpawnTarget }},
+	{"dry_run", "Toggle dry run", func(k *keyMap) *key.Binding { return &k.DryRun }},
+	{"export_plan", "Export dry-run plan", func(k *keyMap) *key.Binding { return &k.ExportPlan }},
+	{"new_worktree", "New worktree from branch", func(k *keyMap) *key.Binding { return &k.NewWorktree }},
+	{"move_item_up", "", func(k *keyMap) *key.Binding { return &k.MoveItemUp }},
+	{"move_item_down", "", func(k *keyMap) *key.Binding { return &k.MoveItemDown }},
+	{"focus", "Focus launched item", func(k *keyMap) *key.Binding { return &k.Focus }},
+	{"restart", "Restart launched item", func(k *keyMap) *key.Binding { return &k.Restart }},
+	{"kill", "Kill launched item", func(k *keyMap) *key.Binding { return &k.Kill }},
+	{"error_log", "Show error log", func(k *keyMap) *key.Binding { return &k.ErrorLog }},
+	{"dismiss", "Dismiss launch progress", func(k *keyMap) *key.Binding { return &k.Dismiss }},
+	{"help", "Show key bindings", func(k *keyMap) *key.Binding { return &k.Help }},
+	{"help_output", "Show the command's --help output", func(k *keyMap) *key.Binding { return &k.HelpOutput }},
+	{"palette", "", func(k *keyMap) *key.Binding { return &k.Palette }},
+	{"reload", "Reload config", func(k *keyMap) *key.Binding { return &k.Reload }},
+	{"edit_config", "Edit config", func(k *keyMap) *key.Binding { return &k.EditConfig }},
+	{"import", "Import tmuxinator/tmuxp files", func(k *keyMap) *key.Binding { return &k.Import }},
+	{"quit", "Quit", func(k *keyMap) *key.Binding { return &k.Quit }},
+}
+
+// newHelp returns the help model rendering the footer ("↑/↓ nav  tab panes  …")
+func newHelp() help.Model {
+	h := help.New()
+	h.ShortSeparator = "  "
+	return h
+}
+
+// defaultKeyMap returns the built-in bindings
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Up:             key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+		Down:           key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+		Expand:         key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "expand")),
+		Collapse:       key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "collapse")),
+		PageUp:         key.NewBinding(key.WithKeys("pgup"), key.WithHelp("pgup", "page up")),
+		PageDown:       key.NewBinding(key.WithKeys("pgdown"), key.WithHelp("pgdn", "page down")),
+		HalfPageUp:     key.NewBinding(key.WithKeys("ctrl+u"), key.WithHelp("ctrl+u", "half page up")),
+		HalfPageDown:   key.NewBinding(key.WithKeys("ctrl+d"), key.WithHelp("ctrl+d", "half page down")),
+		Top:            key.NewBinding(key.WithKeys("home", "g"), key.WithHelp("home/g", "first item")),
+		Bottom:         key.NewBinding(key.WithKeys("end", "G"), key.WithHelp("end/G", "last item")),
+		InfoUp:         key.NewBinding(key.WithKeys("shift+up"), key.WithHelp("shift+↑", "scroll info up")),
+		InfoDown:       key.NewBinding(key.WithKeys("shift+down"), key.WithHelp("shift+↓", "scroll info down")),
+		SwitchPane:     key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "panes")),
+		ToggleInfo:     key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "info")),
+		Select:         key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "expand/select")),
+		Launch:         key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "launch")),
+		ClearSelection: key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clear")),
+		ToggleTmux:     key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "tmux/xterm")),
+		SpawnTarget:    key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "spawn target")),
+		DryRun:         key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "dry run")),
+		ExportPlan:     key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "export plan")),
+		NewWorktree:    key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "new worktree")),
+		MoveItemUp:     key.NewBinding(key.WithKeys("K", "shift+up"), key.WithHelp("K/shift+↑", "move item up")),
+		MoveItemDown:   key.NewBinding(key.WithKeys("J", "shift+down"), key.WithHelp("J/shift+↓", "move item down")),
+		Focus:          key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "focus")),
+		Restart:        key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "restart")),
+		Kill:           key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "kill")),
+		ErrorLog:       key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "error log")),
+		Dismiss:        key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "dismiss")),
+		Help:           key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+		HelpOutput:     key.NewBinding(key.WithKeys("H"), key.WithHelp("H", "--help output")),
+		Palette:        key.NewBinding(key.WithKeys("ctrl+p"), key.WithHelp("ctrl+p", "command palette")),
+		Reload:         key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "reload config")),
+		EditConfig:     key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit config")),
+		Import:         key.NewBinding(key.WithKeys("I"), key.WithHelp("I", "import tmuxinator/tmuxp")),
+		Quit:           key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+	}
+}
+
+// loadKeyMap applies the config's keys: section to the defaults
+// Problems (unknown actions, keys bound to several actions) are returned, not fatal
+func loadKeyMap(overrides map[string]keyList) (keyMap, []string) {
+	keys := defaultKeyMap()
+	var problems []string
+
+	// Sorted so problems are reported in a stable order
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		action, ok := findKeyAction(name)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("keys: unknown action %q", name))
+			continue
+		}
+		binding := action.binding(&keys)
+		bound := normalizeKeys(overrides[name])
+		if len(bound) == 0 {
+			binding.SetEnabled(false)
+			continue
+		}
+		binding.SetKeys(bound...)
+		binding.SetHelp(keyHelp(bound), binding.Help().Desc)
+	}
+
+	return keys, append(problems, keyConflicts(&keys)...)
+}
+
+// findKeyAction looks up an action by its config name
+func findKeyAction(name string) (keyAction, bool) {
+	for _, action := range keyActions {
+		if action.name == name {
+			return action, true
+		}
+	}
+	return keyAction{}, false
+}
+
+// keyConflicts reports keys bound to more than one enabled action of the main view or the spawn dialog
+func keyConflicts(keys *keyMap) []string {
+	var mainView, dialog []keyAction
+	for _, action := range keyActions {
+		if action.name != "move_item_up" && action.name != "move_item_down" {
+			mainView = append(mainView, action)
+		}
+		if slices.Contains(spawnDialogActions, action.name) {
+			dialog = append(dialog, action)
+		}
+	}
+
+	problems := sharedKeys(keys, mainView)
+	for _, problem := range sharedKeys(keys, dialog) {
+		// A conflict of actions in both views is already reported
+		if !slices.Contains(problems, problem) {
+			problems = append(problems, problem)
+		}
+	}
+	return problems
+}
+
+// sharedKeys reports keys bound to more than one enabled action of actions
+func sharedKeys(keys *keyMap, actions []keyAction) []string {
+	owners := make(map[string][]string)
+	var order []string
+	for _, action := range actions {
+		binding := action.binding(keys)
+		if !binding.Enabled() {
+			continue
+		}
+		for _, k := range binding.Keys() {
+			if len(owners[k]) == 0 {
+				order = append(order, k)
+			}
+			owners[k] = append(owners[k], action.name)
+		}
+	}
+
+	var problems []string
+	for _, k := range order {
+		if len(owners[k]) > 1 {
+			problems = append(problems, fmt.Sprintf("keys: %q is bound to %s", keyName(k), strings.Join(owners[k], " and ")))
+		}
+	}
+	return problems
+}
+
+// normalizeKeys converts config spellings to the names Bubble Tea reports
+func normalizeKeys(keys keyList) []string {
+	var result []string
+	for _, k := range keys {
+		k = strings.TrimSpace(k)
+		switch strings.ToLower(k) {
+		case "":
+			continue
+		case "space":
+			k = " "
+		case "pgdn":
+			k = "pgdown"
+		case "return":
+			k = "enter"
+		case "escape":
+			k = "esc"
+		}
+		result = append(result, k)
+	}
+	return result
+}
+
+// keyHelp renders keys for help text ("↑/k")
+func keyHelp(keys []string) string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = keyName(k)
+	}
+	return strings.Join(names, "/")
+}
+
+// keyName is how a key is shown to the user
+func keyName(k string) string {
+	switch k {
+	case " ":
+		return "space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "shift+up":
+		return "shift+↑"
+	case "shift+down":
+		return "shift+↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case "pgdown":
+		return "pgdn"
+	}
+	return k
+}
+
+// keyHint renders a hint like "f: focus" for a binding ("" when it is disabled)
+func keyHint(b key.Binding, text string) string {
+	if !b.Enabled() {
+		return ""
+	}
+	return b.Help().Key + ": " + text
+}
+
+// joinHints joins the non-empty hints of a hint line
+func joinHints(hints ...string) string {
+	var shown []string
+	for _, hint := range hints {
+		if hint != "" {
+			shown = append(shown, hint)
+		}
+	}
+	return strings.Join(shown, "  ")
+}
+
+// withHint appends a hint in parentheses, if there is one
+func withHint(text, hint string) string {
+	if hint == "" {
+		return text
+	}
+	return text + " (" + hint + ")"
+}
+
+// firstKeys combines the first key of each enabled binding into one for hints ("L/esc")
+func firstKeys(bindings ...key.Binding) key.Binding {
+	var keys []string
+	for _, b := range bindings {
+		if b.Enabled() && len(b.Keys()) > 0 {
+			keys = append(keys, b.Keys()[0])
+		}
+	}
+	if len(keys) == 0 {
+		return key.NewBinding(key.WithDisabled())
+	}
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(keyHelp(keys), ""))
+}
+
+// ShortHelp returns the bindings shown in the footer
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.nav(), k.SwitchPane, k.Select, k.Launch, k.EditConfig, k.ClearSelection, k.Quit}
+}
+
+// nav combines up and down into one footer entry ("↑/↓ nav")
+func (k keyMap) nav() key.Binding {
+	if !k.Up.Enabled() || !k.Down.Enabled() {
+		return key.NewBinding(key.WithDisabled())
+	}
+	return key.NewBinding(
+		key.WithKeys(append(k.Up.Keys(), k.Down.Keys()...)...),
+		key.WithHelp(keyName(k.Up.Keys()[0])+"/"+keyName(k.Down.Keys()[0]), "nav"),
+	)
+}
+
+// FullHelp returns every binding, grouped
+func (k keyMap) FullHelp() [][]key.Binding {
+	var groups [][]key.Binding
+	for _, group := range k.groups(true) {
+		groups = append(groups, group.bindings)
+	}
+	return groups
+}
+
+// groups returns the bindings grouped for help; the info toggle only matters in mobile mode
+func (k keyMap) groups(withInfo bool) []keyGroup {
+	panes := []key.Binding{k.SwitchPane, k.Select, k.Launch, k.ClearSelection}
+	if withInfo {
+		panes = []key.Binding{k.SwitchPane, k.ToggleInfo, k.Select, k.Launch, k.ClearSelection}
+	}
+	return []keyGroup{
+		{"Navigation", []key.Binding{k.Up, k.Down, k.Expand, k.Collapse, k.PageUp, k.PageDown, k.HalfPageUp, k.HalfPageDown, k.Top, k.Bottom, k.InfoUp, k.InfoDown, k.HelpOutput}},
+		{"Selection", panes},
+		{"Launching", []key.Binding{k.ToggleTmux, k.SpawnTarget, k.DryRun, k.ExportPlan, k.NewWorktree}},
+		{"Spawn dialog", []key.Binding{k.MoveItemUp, k.MoveItemDown}},
+		{"Launched items", []key.Binding{k.Focus, k.Restart, k.Kill, k.ErrorLog, k.Dismiss}},
+		{"Other", []key.Binding{k.Help, k.Palette, k.Reload, k.EditConfig, k.Import, k.Quit}},
+	}
+}
+
+// footerBindings returns the footer's bindings for a layout mode
+// Mobile screens trade editing and clearing for the info toggle
+func (m model) footerBindings(mode layoutMode) []key.Binding {
+	if mode == layoutMobile {
+		k := m.keys
+		return []key.Binding{k.nav(), k.SwitchPane, k.ToggleInfo, k.Select, k.Launch, k.Quit}
+	}
+	return m.keys.ShortHelp()
+}
+
+// helpLines renders the keymap for a layout mode as "key: action" lines, one per group
+func (m model) helpLines(mode layoutMode) []string {
+	var lines []string
+	for _, group := range m.keys.groups(mode == layoutMobile) {
+		var entries []string
+		for _, binding := range group.bindings {
+			if !binding.Enabled() {
+				continue
+			}
+			entries = append(entries, binding.Help().Key+": "+binding.Help().Desc)
+		}
+		if len(entries) > 0 {
+			lines = append(lines, strings.Join(entries, "  "))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDefaultKeyMapHasNoConflicts(t *testing.T) {
+	keys := defaultKeyMap()
+	if problems := keyConflicts(&keys); len(problems) > 0 {
+		t.Fatalf("default bindings conflict: %q", problems)
+	}
+}
+
+func TestLoadKeyMap(t *testing.T) {
+	tests := []struct {
+		name         string
+		overrides    map[string]keyList
+		action       string   // Action to check after loading
+		wantKeys     []string // nil = action disabled
+		wantHelp     string
+		wantProblems []string
+	}{
+		{"no overrides", nil, "quit", []string{"q"}, "q", nil},
+		{"rebind", map[string]keyList{"quit": {"ctrl+q"}}, "quit", []string{"ctrl+q"}, "ctrl+q", nil},
+		{"several keys", map[string]keyList{"up": {"up", "W"}}, "up", []string{"up", "W"}, "↑/W", nil},
+		{"config spellings", map[string]keyList{"launch": {"Return", " space "}, "select": {"s"}}, "launch", []string{"enter", " "}, "enter/space", nil},
+		{"empty list disables", map[string]keyList{"edit_config": {}}, "edit_config", nil, "", nil},
+		{"blank keys disable", map[string]keyList{"edit_config": {"", " "}}, "edit_config", nil, "", nil},
+		{
+			"unknown action",
+			map[string]keyList{"launch_rockets": {"R"}},
+			"quit", []string{"q"}, "q",
+			[]string{`keys: unknown action "launch_rockets"`},
+		},
+		{
+			"conflict with a default",
+			map[string]keyList{"quit": {"e"}},
+			"quit", []string{"e"}, "e",
+			[]string{`keys: "e" is bound to edit_config and quit`},
+		},
+		{
+			"conflict resolved by disabling the other action",
+			map[string]keyList{"quit": {"e"}, "edit_config": {}},
+			"quit", []string{"e"}, "e",
+			nil,
+		},
+		{
+			"dialog keys may repeat main view keys",
+			map[string]keyList{"move_item_up": {"f"}},
+			"move_item_up", []string{"f"}, "f",
+			nil,
+		},
+		{
+			"conflict within the spawn dialog",
+			map[string]keyList{"move_item_down": {"w"}},
+			"move_item_down", []string{"w"}, "w",
+			[]string{`keys: "w" is bound to spawn_target and move_item_down`},
+		},
+		{
+			"conflict in both views reported once",
+			map[string]keyList{"up": {"q"}},
+			"up", []string{"q"}, "q",
+			[]string{`keys: "q" is bound to up and quit`},
+		},
+		{
+			"problems in a stable order",
+			map[string]keyList{"zzz": {"z"}, "aaa": {"a"}, "dismiss": {"escape", "space"}},
+			"dismiss", []string{"esc", " "}, "esc/space",
+			[]string{`keys: unknown action "aaa"`, `keys: unknown action "zzz"`, `keys: "space" is bound to select and dismiss`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, problems := loadKeyMap(tt.overrides)
+			if !reflect.DeepEqual(problems, tt.wantProblems) {
+				t.Errorf("problems = %q, want %q", problems, tt.wantProblems)
+			}
+
+			action, ok := findKeyAction(tt.action)
+			if !ok {
+				t.Fatalf("no action %q", tt.action)
+			}
+			binding := action.binding(&keys)
+			if tt.wantKeys == nil {
+				if binding.Enabled() {
+					t.Errorf("%s is enabled with %q, want it disabled", tt.action, binding.Keys())
+				}
+				return
+			}
+			if !binding.Enabled() {
+				t.Fatalf("%s is disabled", tt.action)
+			}
+			if !reflect.DeepEqual(binding.Keys(), tt.wantKeys) {
+				t.Errorf("%s keys = %q, want %q", tt.action, binding.Keys(), tt.wantKeys)
+			}
+			if binding.Help().Key != tt.wantHelp {
+				t.Errorf("%s help = %q, want %q", tt.action, binding.Help().Key, tt.wantHelp)
+			}
+		})
+	}
+}
+
+func TestKeyListYAML(t *testing.T) {
+	tests := []struct {
+		yaml string
+		want map[string]keyList
+	}{
+		{"quit: q", map[string]keyList{"quit": {"q"}}},
+		{"quit: [q, ctrl+q]", map[string]keyList{"quit": {"q", "ctrl+q"}}},
+		{"quit: []", map[string]keyList{"quit": {}}},
+		{`quit: ""`, map[string]keyList{"quit": {}}},
+	}
+	for _, tt := range tests {
+		var got map[string]keyList
+		if err := yaml.Unmarshal([]byte(tt.yaml), &got); err != nil {
+			t.Errorf("%s: %v", tt.yaml, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.yaml, got, tt.want)
+		}
+	}
+}
+
+func TestKeyHints(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]keyList
+		hint      func(m model) string
+		wantHint  string
+	}{
+		{"default", nil, func(m model) string { return keyHint(m.keys.Focus, "focus") }, "f: focus"},
+		{"rebound", map[string]keyList{"focus": {"F", "ctrl+f"}}, func(m model) string { return keyHint(m.keys.Focus, "focus") }, "F/ctrl+f: focus"},
+		{"disabled", map[string]keyList{"focus": {}}, func(m model) string { return keyHint(m.keys.Focus, "focus") }, ""},
+		{"toast", nil, func(m model) string { return m.withLogHint("✗ Launch failed") }, "✗ Launch failed (L: error log)"},
+		{"toast without the error log", map[string]keyList{"error_log": {}}, func(m model) string { return m.withLogHint("✗ Launch failed") }, "✗ Launch failed"},
+		{
+			"spawn dialog",
+			nil,
+			func(m model) string { return m.spawnDialogHints() },
+			"tab: section  ↑/↓: choose  K/J: move item  w: target  enter: launch  esc/q: cancel",
+		},
+		{
+			"spawn dialog rebound",
+			map[string]keyList{"move_item_up": {"u"}, "move_item_down": {"n"}, "spawn_target": {}, "dismiss": {}},
+			func(m model) string { return m.spawnDialogHints() },
+			"tab: section  ↑/↓: choose  u/n: move item  enter: launch  q: cancel",
+		},
+		{
+			"error log",
+			nil,
+			func(m model) string { return m.errorLogHints() },
+			"↑/↓: scroll  pgup/pgdn: page  L/esc/q: close",
+		},
+		{
+			"error log without paging",
+			map[string]keyList{"page_up": {}, "page_down": {}},
+			func(m model) string { return m.errorLogHints() },
+			"↑/↓: scroll  L/esc/q: close",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, problems := loadKeyMap(tt.overrides)
+			if len(problems) > 0 {
+				t.Fatalf("problems: %q", problems)
+			}
+			if got := tt.hint(model{keys: keys}); got != tt.wantHint {
+				t.Errorf("hint = %q, want %q", got, tt.wantHint)
+			}
+		})
+	}
+}
+
+func TestSpawnDialogKeys(t *testing.T) {
+	keys, _ := loadKeyMap(map[string]keyList{"move_item_down": {"n"}, "quit": {"ctrl+q"}})
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	tests := []struct {
+		name       string
+		msg        tea.KeyMsg
+		wantOrder  []string
+		wantCursor int
+		wantOpen   bool
+	}{
+		{"rebound move down", runes("n"), []string{"b", "a", "c"}, 1, true},
+		{"old move down key does nothing", runes("J"), []string{"a", "b", "c"}, 0, true},
+		{"default move up at the top", tea.KeyMsg{Type: tea.KeyShiftUp}, []string{"a", "b", "c"}, 0, true},
+		{"q is no longer quit", runes("q"), []string{"a", "b", "c"}, 0, true},
+		{"rebound quit closes", tea.KeyMsg{Type: tea.KeyCtrlQ}, nil, 0, false},
+		{"dismiss closes", tea.KeyMsg{Type: tea.KeyEsc}, nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := model{
+				keys:            keys,
+				showSpawnDialog: true,
+				dialogSection:   sectionPanes,
+				dialogItems:     []launchItem{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+			}
+			next, _ := m.updateSpawnDialog(tt.msg)
+			m = next.(model)
+
+			var order []string
+			for _, item := range m.dialogItems {
+				order = append(order, item.Name)
+			}
+			if m.showSpawnDialog != tt.wantOpen || !reflect.DeepEqual(order, tt.wantOrder) || m.dialogCursor != tt.wantCursor {
+				t.Errorf("open %v, items %q, cursor %d; want %v, %q, %d", m.showSpawnDialog, order, m.dialogCursor, tt.wantOpen, tt.wantOrder, tt.wantCursor)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
@@ -8,11 +9,104 @@ import (
 )
 
 func main() {
+	// Shell integration: tui-launcher shell-init bash|zsh|fish
+	if len(os.Args) > 1 && os.Args[1] == "shell-init" {
+		shell := ""
+		if len(os.Args) > 2 {
+			shell = os.Args[2]
+		}
+		script, err := shellInit(shell)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(script)
+		return
+	}
+
+	// Control socket: tui-launcher serve / tui-launcher client <method>
+	if len(os.Args) > 1 && (os.Args[1] == "serve" || os.Args[1] == "client") {
+		run := runServe
+		if os.Args[1] == "client" {
+			run = runClient
+		}
+		if err := run(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// tmuxinator/tmuxp: tui-launcher import <file>... / tui-launcher export <project>
+	if len(os.Args) > 1 && (os.Args[1] == "import" || os.Args[1] == "export") {
+		run := runImport
+		if os.Args[1] == "export" {
+			run = runExport
+		}
+		if err := run(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Popup mode: tui-launcher popup [--width W] [--height H] [--print-binding]
+	if len(os.Args) > 1 && os.Args[1] == "popup" {
+		if err := runPopup(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	dryRun := flag.Bool("dry-run", false, "show spawn plans instead of launching; with an item name, print its plan and exit")
+	script := flag.Bool("script", false, "with --dry-run <item>, print the plan as a standalone shell script")
+	cdFD := flag.Int("cd-fd", 0, "write the project directory picked with Enter to this file descriptor (1 = stdout, the UI then draws on stderr) instead of ~/.tui-launcher_cd_target")
+	popupPane := flag.String("popup-pane", "", "run as the popup of this tmux pane (set by the popup command)")
+	flag.Parse()
+
+	if *popupPane != "" {
+		if err := enterPopup(*popupPane); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *cdFD > 0 {
+		if err := setCDFD(*cdFD); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Subcommands only work as the first argument
+	if !*dryRun && flag.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: unexpected argument %q (subcommands go before any flags)\n", flag.Arg(0))
+		os.Exit(2)
+	}
+
+	// Non-interactive dry run: tui-launcher --dry-run [--script] <name or path>
+	if *dryRun && flag.NArg() > 0 {
+		if err := printPlan(flag.Arg(0), *script); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Create initial model
+	// Its theme queries the terminal background, which must happen before Bubble Tea owns the terminal
 	m := initialModel()
+	m.dryRun = *dryRun
+	m.popup = *popupPane != ""
 
 	// Create program with alt screen and mouse support
-	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithMouseAllMotion())
+	opts := []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithMouseAllMotion()}
+	if *cdFD == 1 {
+		// Stdout carries the cd target, so it can't carry the UI
+		opts = append(opts, tea.WithOutput(os.Stderr))
+	}
+	p := tea.NewProgram(m, opts...)
 
 	// Run the program
 	if _, err := p.Run(); err != nil {
@@ -8,16 +8,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/lipgloss"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
 	"gopkg.in/yaml.v3"
 )
 
-var (
-	selectedStyle = lipgloss.NewStyle().Bold(true)
-)
-
 // footerTick sends periodic messages to animate footer scrolling
 func footerTick() tea.Cmd {
 	return tea.Tick(200*time.Millisecond, func(t time.Time) tea.Msg {
@@ -29,8 +27,9 @@ func footerTick() tea.Cmd {
 func initialModel() model {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
+	terminal := detectTerminal()
 
-	return model{
+	m := model{
 		width:          80,
 		height:         24,
 		cursor:         0,
@@ -56,12 +55,22 @@ func initialModel() model {
 		showSpawnDialog: false,
 		selectedLayout: layoutTiled,
 		layoutCursor:   0,
+		spawnTarget:    targetAuto,
+		tracked:        make(map[string][]trackedPane),
+		docs:           make(map[string]itemDoc),
+		gitStatus:      make(map[string]gitStatus),
 		spinner:        s,
+		keys:           defaultKeyMap(),
+		theme:          newTheme(autoThemeName(), builtinThemes[autoThemeName()]),
+		icons:          iconSets[autoIconStyle(terminal)],
+		help:           newHelp(),
 		loading:        true,
-		terminalType:   detectTerminal(),
+		terminalType:   terminal,
 		insideTmux:     isInsideTmux(),
 		useTmux:        true, // Default to tmux mode
 	}
+	m.applyIcons()
+	return m
 }
 
 // Init initializes the model
@@ -69,18 +78,98 @@ func (m model) Init() tea.Cmd {
 	return tea.Batch(
 		m.spinner.Tick,
 		loadConfig,
+		gitTick(),
 	)
 }
 
 // Update handles messages and updates the model
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if m, ok := next.(model); ok {
+		// Whatever moved a cursor (keys, mouse, reloads, resizes), keep it in view
+		m.followCursors()
+		return m, cmd
+	}
+	return next, cmd
+}
+
+// update handles a message
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
+		// The error log overlay takes all keys while open
+		if m.showErrorLog {
+			switch {
+			case msg.String() == "ctrl+c":
+				return m, tea.Quit
+			case key.Matches(msg, m.keys.Dismiss, m.keys.ErrorLog, m.keys.Quit):
+				m.showErrorLog = false
+			case key.Matches(msg, m.keys.Up):
+				m.scrollErrorLog(-1)
+			case key.Matches(msg, m.keys.Down):
+				m.scrollErrorLog(1)
+			case key.Matches(msg, m.keys.PageUp):
+				m.scrollErrorLog(-(m.height - 2))
+			case key.Matches(msg, m.keys.PageDown):
+				m.scrollErrorLog(m.height - 2)
+			}
+			return m, nil
+		}
+
+		// So do help, the palette, the spawn dialog and the new worktree prompt
+		if m.showHelp {
+			return m.updateHelp(msg)
+		}
+		if m.showPalette {
+			return m.updatePalette(msg)
+		}
+		if m.showSpawnDialog {
+			return m.updateSpawnDialog(msg)
+		}
+		if m.showWorktreePrompt {
+			return m.updateWorktreePrompt(msg)
+		}
+		if m.showAIPrompt {
+			return m.updateAIPrompt(msg)
+		}
+		if m.showImportPrompt {
+			return m.updateImportPrompt(msg)
+		}
+
+		switch {
+		case msg.String() == "ctrl+c", key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
 
-		case "tab":
+		case key.Matches(msg, m.keys.ErrorLog):
+			// Open the error log at its newest entries
+			m.showErrorLog = true
+			m.errorLogOffset = len(m.errorLogLines())
+			m.scrollErrorLog(0)
+
+		case key.Matches(msg, m.keys.Help):
+			m.showHelp = true
+			m.helpOffset = 0
+
+		case key.Matches(msg, m.keys.Palette):
+			m.openPalette()
+
+		case key.Matches(msg, m.keys.Reload):
+			return m, tea.Batch(loadConfig, m.showToast("Reloading config…", false))
+
+		case key.Matches(msg, m.keys.Dismiss):
+			// Dismiss the launch progress view
+			m.launch.visible = false
+
+		case key.Matches(msg, m.keys.InfoUp):
+			// Scroll the info pane (docs can be long)
+			_, height := m.infoViewport()
+			m.scrollInfo(-max(height/2, 1))
+
+		case key.Matches(msg, m.keys.InfoDown):
+			_, height := m.infoViewport()
+			m.scrollInfo(max(height/2, 1))
+
+		case key.Matches(msg, m.keys.SwitchPane):
 			// Handle Tab key based on layout mode
 			mode := m.getLayoutMode()
 			switch mode {
@@ -101,69 +190,57 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Update info pane after switching panes
 			m.updateInfoPane()
 
-		case "i":
+		case key.Matches(msg, m.keys.HelpOutput):
+			return m, m.loadHelpDoc()
+
+		case key.Matches(msg, m.keys.ToggleInfo):
 			// Toggle info pane in mobile mode
 			if m.getLayoutMode() == layoutMobile {
 				m.showingInfo = !m.showingInfo
 			}
 
-		case "up", "k":
+		case key.Matches(msg, m.keys.Up):
 			// Navigate up in the active pane
-			mode := m.getLayoutMode()
-			if mode == layoutDesktop {
-				if m.activePane == paneGlobal {
-					if m.globalCursor > 0 {
-						m.globalCursor--
-					}
-				} else {
-					if m.projectCursor > 0 {
-						m.projectCursor--
-					}
-				}
-			} else {
-				// Compact/mobile mode - navigate in current view
-				if m.showingProjects {
-					if m.projectCursor > 0 {
-						m.projectCursor--
-					}
-				} else {
-					if m.globalCursor > 0 {
-						m.globalCursor--
-					}
-				}
-			}
+			m.moveCursor(-1)
 			// Update info pane after cursor movement
 			m.updateInfoPane()
 
-		case "down", "j":
+		case key.Matches(msg, m.keys.Down):
 			// Navigate down in the active pane
-			mode := m.getLayoutMode()
-			if mode == layoutDesktop {
-				if m.activePane == paneGlobal {
-					if m.globalCursor < len(m.globalTreeItems)-1 {
-						m.globalCursor++
-					}
-				} else {
-					if m.projectCursor < len(m.projectTreeItems)-1 {
-						m.projectCursor++
-					}
-				}
-			} else {
-				// Compact/mobile mode - navigate in current view
-				if m.showingProjects {
-					if m.projectCursor < len(m.projectTreeItems)-1 {
-						m.projectCursor++
-					}
+			m.moveCursor(1)
+			// Update info pane after cursor movement
+			m.updateInfoPane()
+
+		case key.Matches(msg, m.keys.PageUp, m.keys.PageDown, m.keys.HalfPageUp, m.keys.HalfPageDown):
+			// Mobile mode's info view replaces the tree, so pages scroll it instead
+			if m.getLayoutMode() == layoutMobile && m.showingInfo {
+				_, height := m.infoViewport()
+				if key.Matches(msg, m.keys.PageUp, m.keys.HalfPageUp) {
+					m.scrollInfo(-max(height/2, 1))
 				} else {
-					if m.globalCursor < len(m.globalTreeItems)-1 {
-						m.globalCursor++
-					}
+					m.scrollInfo(max(height/2, 1))
 				}
+				break
 			}
-			// Update info pane after cursor movement
+			page := m.treeRows()
+			if key.Matches(msg, m.keys.HalfPageUp, m.keys.HalfPageDown) {
+				page = max(page/2, 1)
+			}
+			if key.Matches(msg, m.keys.PageUp, m.keys.HalfPageUp) {
+				page = -page
+			}
+			m.moveCursor(page)
+			m.updateInfoPane()
+
+		case key.Matches(msg, m.keys.Top):
+			m.moveCursor(-len(m.globalTreeItems) - len(m.projectTreeItems))
 			m.updateInfoPane()
 
-		case "right", "l":
+		case key.Matches(msg, m.keys.Bottom):
+			m.moveCursor(len(m.globalTreeItems) + len(m.projectTreeItems))
+			m.updateInfoPane()
+
+		case key.Matches(msg, m.keys.Expand):
 			// Expand current category in active pane
 			mode := m.getLayoutMode()
 			if mode == layoutDesktop {
@@ -205,7 +282,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-		case "left", "h":
+		case key.Matches(msg, m.keys.Collapse):
 			// Collapse current category in active pane
 			mode := m.getLayoutMode()
 			if mode == layoutDesktop {
@@ -247,7 +324,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-		case " ":
+		case key.Matches(msg, m.keys.Select):
 			// Context-aware action: expand/collapse categories, or toggle selection for commands
 			mode := m.getLayoutMode()
 			var currentItem launchItem
@@ -311,15 +388,71 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-		case "c":
+		case key.Matches(msg, m.keys.ClearSelection):
 			// Clear all selections
 			m.selectedItems = make(map[string]bool)
 
-		case "t":
+		case key.Matches(msg, m.keys.ToggleTmux):
 			// Toggle tmux/xterm mode
 			m.useTmux = !m.useTmux
 
-		case "e":
+		case key.Matches(msg, m.keys.DryRun):
+			// Toggle dry run: Enter shows the spawn plan instead of launching
+			m.dryRun = !m.dryRun
+			if !m.dryRun {
+				m.plan = nil
+				m.updateInfoPane()
+			}
+
+		case key.Matches(msg, m.keys.ExportPlan):
+			// Export the last dry-run plan as a shell script
+			if m.plan == nil {
+				return m, m.showToast(withHint("No plan to export", keyHint(m.keys.DryRun, "dry run first")), true)
+			}
+			cwd, err := os.Getwd()
+			if err == nil {
+				var path string
+				path, err = writePlanScript(m.plan, cwd)
+				if err == nil {
+					return m, m.showToast("Plan written to "+path, false)
+				}
+			}
+			m.logError(err)
+			return m, m.showToast("✗ "+err.Error(), true)
+
+		case key.Matches(msg, m.keys.NewWorktree):
+			// New worktree (from a branch) for the current project
+			if project, ok := m.currentProject(); ok {
+				m.openWorktreePrompt(project)
+			}
+
+		case key.Matches(msg, m.keys.SpawnTarget):
+			// Cycle spawn target for batch launches
+			m.spawnTarget = (m.spawnTarget + 1) % (targetSwitchSession + 1)
+
+		case key.Matches(msg, m.keys.Focus, m.keys.Restart, m.keys.Kill):
+			// Focus/restart/kill whatever the current item launched
+			currentItem, ok := m.currentItem()
+			if !ok {
+				break
+			}
+			tracked := m.tracked[currentItem.Path]
+			if len(tracked) == 0 {
+				break
+			}
+			switch {
+			case key.Matches(msg, m.keys.Focus):
+				return m, focusTracked(tracked)
+			case key.Matches(msg, m.keys.Restart):
+				return m, restartTracked(tracked)
+			case key.Matches(msg, m.keys.Kill):
+				return m, killTracked(tracked)
+			}
+
+		case key.Matches(msg, m.keys.Import):
+			m.openImportPrompt()
+
+		case key.Matches(msg, m.keys.EditConfig):
 			// Edit config file
 			if m.insideTmux {
 				// If inside tmux, spawn editor in a new split
@@ -332,7 +465,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				)
 			}
 
-		case "enter":
+		case key.Matches(msg, m.keys.Launch):
 			// Launch selected items or current item
 			mode := m.getLayoutMode()
 			var currentItem launchItem
@@ -371,8 +504,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if currentItem.ItemType == typeCategory && currentItem.Cwd != "" {
 					// This is a project category with a directory - CD into it
 					if err := writeCDTarget(currentItem.Cwd); err != nil {
-						m.err = fmt.Errorf("failed to write CD target: %w", err)
-						return m, nil
+						err = fmt.Errorf("failed to write CD target: %w", err)
+						m.logError(err)
+						return m, m.showToast(m.withLogHint("✗ "+err.Error()), true)
 					}
 					return m, tea.Quit
 				}
@@ -385,62 +519,65 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// Collect all selected items from both panes
 					for _, ti := range m.globalTreeItems {
 						if m.selectedItems[ti.item.Path] {
-							itemsToLaunch = append(itemsToLaunch, ti.item)
+							itemsToLaunch = append(itemsToLaunch, m.contextItem(ti.item, ti.item.Prompt))
 						}
 					}
 					for _, ti := range m.projectTreeItems {
 						if m.selectedItems[ti.item.Path] {
-							itemsToLaunch = append(itemsToLaunch, ti.item)
+							itemsToLaunch = append(itemsToLaunch, m.contextItem(ti.item, ti.item.Prompt))
 						}
 					}
 
+					if len(itemsToLaunch) > 1 {
+						// Let the user pick layout, pane order and target first
+						m.openSpawnDialog(itemsToLaunch)
+						return m, nil
+					}
 					if len(itemsToLaunch) > 0 {
-						// Use default layout for batch launch
-						return m, spawnMultiple(itemsToLaunch, m.selectedLayout)
+						layout, opts := m.selectedLayout, batchOptions{target: m.spawnTarget}
+						return m, m.startLaunch(fmt.Sprintf("%d selected", len(itemsToLaunch)), itemsToLaunch, itemsToLaunch, func(s *spawner) spawnCompleteMsg {
+							return s.multiple(itemsToLaunch, layout, opts)
+						})
 					}
 
 				} else {
 					// No selection - launch current item if it's a command or profile
 					if currentItem.ItemType == typeCommand {
-						// Launch single command
-						if !m.useTmux {
-							// Non-tmux mode: run command directly in current terminal
-							return m, tea.Sequence(
-								tea.Quit,
-								runCommandDirectly(currentItem),
-							)
-						} else {
-							// Tmux mode: use configured spawn mode
-							return m, spawnSingle(currentItem, currentItem.DefaultSpawn)
+						// AI tools with prompt: ask take the prompt first
+						if currentItem.IsAI && currentItem.Prompt == promptAsk {
+							m.openAIPrompt(currentItem)
+							return m, nil
 						}
+						// Launch single command
+						return m, m.launchCommand(m.contextItem(currentItem, currentItem.Prompt))
 
 					} else if currentItem.ItemType == typeProfile {
 						// Launch profile (convert panes to launch items)
-						var itemsToLaunch []launchItem
-						for i, pane := range currentItem.Panes {
-							item := launchItem{
-								Name:    fmt.Sprintf("%s-pane-%d", currentItem.Name, i),
-								Command: pane.Command,
-								Cwd:     expandPath(pane.Cwd),
-							}
-							itemsToLaunch = append(itemsToLaunch, item)
-						}
-						return m, spawnMultiple(itemsToLaunch, currentItem.Layout)
+						return m, m.launchProfile(currentItem)
 					}
 				}
 			}
 		}
 
 	case tea.MouseMsg:
+		// Overlays are keyboard-only
+		if m.showErrorLog || m.showHelp || m.showPalette || m.showSpawnDialog || m.showWorktreePrompt || m.showAIPrompt || m.showImportPrompt {
+			return m, nil
+		}
 		switch msg.Type {
 		case tea.MouseLeft:
+			// Dragging with the button held reports more MouseLeft events; only presses click
+			if msg.Action != tea.MouseActionPress {
+				break
+			}
+
 			// Click to switch panes in desktop mode
 			// GOLDEN RULE #3: Match mouse detection to layout (X for horizontal split)
 			mode := m.getLayoutMode()
 			if mode == layoutDesktop {
-				leftWidth, _, _, _ := m.calculateLayout()
-				// Header is 3 lines tall
-				if msg.Y > 3 {
+				leftWidth, _, treeHeight, _ := m.calculateLayout()
+				// Header is 3 lines tall; the tree panes end with their bottom border
+				if msg.Y >= 3 && msg.Y < 3+treeHeight+2 {
 					if msg.X < leftWidth {
 						m.activePane = paneGlobal
 					} else {
@@ -450,59 +587,20 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-		case tea.MouseWheelUp:
-			// Scroll up in active pane
-			mode := m.getLayoutMode()
-			if mode == layoutDesktop {
-				if m.activePane == paneGlobal {
-					if m.globalCursor > 0 {
-						m.globalCursor--
-					}
-				} else {
-					if m.projectCursor > 0 {
-						m.projectCursor--
-					}
-				}
-			} else {
-				// Compact/mobile mode
-				if m.showingProjects {
-					if m.projectCursor > 0 {
-						m.projectCursor--
-					}
-				} else {
-					if m.globalCursor > 0 {
-						m.globalCursor--
-					}
-				}
+			// Click a row to move the cursor, its glyphs to expand or select; double-click launches
+			if m.clickTree(msg) {
+				return m.runAction("launch")
 			}
+
+		case tea.MouseWheelUp:
+			// Scroll up in active pane: the cursor moves like ↑ and the viewport follows it
+			m.moveCursor(-1)
 			// Update info pane after scrolling
 			m.updateInfoPane()
 
 		case tea.MouseWheelDown:
 			// Scroll down in active pane
-			mode := m.getLayoutMode()
-			if mode == layoutDesktop {
-				if m.activePane == paneGlobal {
-					if m.globalCursor < len(m.globalTreeItems)-1 {
-						m.globalCursor++
-					}
-				} else {
-					if m.projectCursor < len(m.projectTreeItems)-1 {
-						m.projectCursor++
-					}
-				}
-			} else {
-				// Compact/mobile mode
-				if m.showingProjects {
-					if m.projectCursor < len(m.projectTreeItems)-1 {
-						m.projectCursor++
-					}
-				} else {
-					if m.globalCursor < len(m.globalTreeItems)-1 {
-						m.globalCursor++
-					}
-				}
-			}
+			m.moveCursor(1)
 			// Update info pane after scrolling
 			m.updateInfoPane()
 		}
@@ -512,12 +610,44 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 
 	case configLoadedMsg:
+		if msg.err != nil && !m.loading {
+			// A broken reload keeps the config that is running
+			err := fmt.Errorf("reload config: %w", msg.err)
+			m.logError(err)
+			hint := "kept the current config"
+			if logHint := keyHint(m.keys.ErrorLog, "error log"); logHint != "" {
+				hint += ", " + logHint
+			}
+			return m, m.showToast(withHint("✗ "+firstLine(err.Error()), hint), true)
+		}
 		m.loading = false
 		m.config = msg.config
 		m.err = msg.err
+		m.docs = make(map[string]itemDoc) // info_file paths may have changed
 		if msg.err == nil {
+			// Key binding, theme and icon problems are reported but don't stop the launcher
+			var problems, themeProblems, iconProblems []string
+			m.keys, problems = loadKeyMap(msg.config.Keys)
+			m.theme, themeProblems = loadTheme(msg.config)
+			m.icons, iconProblems = loadIcons(msg.config, m.terminalType)
+			m.applyIcons()
+			m.theme.applyToHelp(&m.help)
+			problems = append(append(problems, themeProblems...), iconProblems...)
+
 			// Build trees from config (split into global and project panes)
 			m.globalItems, m.projectItems = buildTreeFromConfig(msg.config)
+			allItems := append(append([]launchItem{}, m.globalItems...), m.projectItems...)
+			problems = append(problems, duplicatePaths(allItems)...)
+			problems = append(problems, invalidSessionNames(m.projectItems)...)
+			problems = append(problems, unknownSettings(allItems)...)
+
+			for _, problem := range problems {
+				m.logError(fmt.Errorf("%s", problem))
+			}
+			var toast tea.Cmd
+			if len(problems) > 0 {
+				toast = m.showToast(m.withLogHint(fmt.Sprintf("✗ %d config problem(s)", len(problems))), true)
+			}
 			m.globalTreeItems = flattenTree(m.globalItems, m.globalExpanded)
 			m.projectTreeItems = flattenTree(m.projectItems, m.projectExpanded)
 
@@ -526,16 +656,129 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.rootItems = append(m.globalItems, m.projectItems...)
 			m.treeItems = flattenTree(m.rootItems, m.expandedItems)
 
+			// Worktrees known from earlier refreshes (config was reloaded)
+			m.applyWorktrees()
+
 			// Update info pane for initial selection
 			m.updateInfoPane()
+
+			return m, tea.Batch(m.refreshGitStatus(), m.requestDoc(), toast)
 		}
 
+	case rpcMsg:
+		return m.handleRPC(msg)
+
 	case spawnCompleteMsg:
-		m.err = msg.err
+		// Launch errors are reported in place; the launcher stays open
+		m.finishRPCLaunch(msg)
+		feedbackCmd := m.finishLaunch(msg)
 		// Clear selections after launch
 		if msg.err == nil {
 			m.selectedItems = make(map[string]bool)
 		}
+		cmd := m.trackLaunch(msg)
+		m.updateInfoPane()
+		if msg.err == nil && msg.attach != "" && !m.serving {
+			return m, tea.Batch(cmd, feedbackCmd, attachSessionCmd(msg.attach))
+		}
+		return m, tea.Batch(cmd, feedbackCmd)
+
+	case attachDoneMsg:
+		if msg.err != nil {
+			err := fmt.Errorf("attach to session %s: %w", msg.session, msg.err)
+			m.logError(err)
+			return m, m.showToast(m.withLogHint("✗ "+err.Error()), true)
+		}
+
+	case planMsg:
+		m.plan = msg.plan
+		m.infoContent = withHint("Dry run: "+msg.plan.title, keyHint(m.keys.ExportPlan, "export script")) + "\n\n" + strings.Join(msg.plan.lines(), "\n")
+
+	case gitTickMsg:
+		return m, tea.Batch(m.refreshGitStatus(), gitTick())
+
+	case gitStatusMsg:
+		previous := m.gitStatus[msg.path]
+		m.gitStatus[msg.path] = msg.status
+		if !sameWorktrees(previous.worktrees, msg.status.worktrees) {
+			m.applyWorktrees()
+		} else if msg.path == m.infoPath {
+			m.updateInfoPane()
+		}
+		return m, waitForGitStatus(msg.updates)
+
+	case gitRefreshDoneMsg:
+		m.gitRefreshing = false
+		// Worktrees found by this refresh get their own status right away
+		for _, project := range gitProjects(m.projectItems) {
+			if _, ok := m.gitStatus[project.Path]; !ok {
+				return m, m.refreshGitStatus()
+			}
+		}
+
+	case worktreeCreatedMsg:
+		if msg.err != nil {
+			m.logError(msg.err)
+			return m, m.showToast(m.withLogHint("✗ "+firstLine(msg.err.Error())), true)
+		}
+		return m, m.launchInWorktree(msg)
+
+	case importDoneMsg:
+		for _, warning := range msg.warnings {
+			m.logError(fmt.Errorf("import: %s", warning))
+		}
+		if msg.err != nil {
+			m.logError(msg.err)
+			return m, m.showToast(m.withLogHint("✗ Import failed: "+firstLine(msg.err.Error())), true)
+		}
+		text := "✓ Imported " + importSummary(msg.projects)
+		if len(msg.warnings) > 0 {
+			text = m.withLogHint(text + fmt.Sprintf(", %d warning(s)", len(msg.warnings)))
+		}
+		return m, tea.Batch(m.showToast(text, false), loadConfig)
+
+	case docLoadedMsg:
+		m.docs[msg.path] = msg.doc
+		m.updateInfoPane()
+
+	case toastExpiredMsg:
+		// Ignore timers from toasts that were already replaced
+		if msg.id == m.toastSeq {
+			m.toastText = ""
+		}
+
+	case statusTickMsg:
+		return m, pollTick
+
+	case paneStatusMsg:
+		if msg.err == nil {
+			m.applyPaneStatus(msg.panes)
+			m.updateInfoPane()
+		}
+		// Keep polling while anything we launched is still alive
+		if msg.fromTick || !m.statusPolling {
+			m.statusPolling = m.hasRunningPanes()
+			if m.statusPolling {
+				return m, statusTick()
+			}
+		}
+
+	case directRunMsg:
+		// Before hooks passed: hand the terminal over to the command
+		return m, tea.Sequence(
+			tea.Quit,
+			runCommandDirectly(msg.item),
+		)
+
+	case stageMsg:
+		m.applyStage(msg)
+		feedbackCmd := m.applyStageToLaunch(msg)
+		m.updateInfoPane()
+		return m, tea.Batch(waitForStage(msg.stages), feedbackCmd)
+
+	case processExitedMsg:
+		m.applyProcessExit(msg.pid, msg.exitCode)
+		m.updateInfoPane()
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -543,7 +786,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
-	return m, nil
+	// Whatever changed, make sure the selected item's docs are on their way
+	return m, m.requestDoc()
 }
 
 // viewLeftPane renders the global tools tree (left pane in desktop mode)
@@ -562,24 +806,7 @@ func (m model) viewLeftPane(width, height int) string {
 	if len(m.globalTreeItems) == 0 {
 		lines = append(lines, "(no global tools)")
 	} else {
-		for i, ti := range m.globalTreeItems {
-			selected := m.selectedItems[ti.item.Path]
-			expanded := m.globalExpanded[ti.item.Path]
-			line := renderTreeItem(ti, m.globalCursor, i, selected, expanded)
-
-			// GOLDEN RULE #2: Truncate to prevent wrapping
-			maxWidth := width - 4 // Account for padding
-			if len(line) > maxWidth {
-				line = line[:maxWidth-1] + "…"
-			}
-
-			// Highlight cursor
-			if i == m.globalCursor && m.activePane == paneGlobal {
-				line = selectedStyle.Render(line)
-			}
-
-			lines = append(lines, line)
-		}
+		lines = append(lines, m.treeViewport(m.globalTreeItems, m.globalCursor, m.globalOffset, m.activePane == paneGlobal, m.globalExpanded, width, height-2)...)
 	}
 
 	// Fill to exact height (GOLDEN RULE #1: height already accounts for borders)
@@ -595,6 +822,42 @@ func (m model) viewLeftPane(width, height int) string {
 	return strings.Join(lines, "\n")
 }
 
+// treeLine renders one tree row in the theme's styles
+// The cursor row of the focused pane is highlighted as a whole; other rows color
+// the item by kind and each badge by its state
+func (m model) treeLine(ti launchTreeItem, index, cursor int, focused, expanded bool, width int) string {
+	selected := m.selectedItems[ti.item.Path]
+	plain := renderTreeItem(ti, cursor, index, selected, expanded, "", m.icons)
+	maxWidth := width - 4 // Account for padding
+
+	if index == cursor && focused {
+		if badge := m.treeBadge(ti.item.Path); badge != "" {
+			plain += " " + badge
+		}
+		// GOLDEN RULE #2: Truncate to prevent wrapping
+		return m.theme.cursor.Render(ansi.Truncate(plain, maxWidth, "…"))
+	}
+
+	style := lipgloss.NewStyle()
+	switch {
+	case selected:
+		style = m.theme.selected
+	case ti.item.ItemType == typeCategory:
+		style = m.theme.category
+	case ti.item.ItemType == typeProfile:
+		style = m.theme.profile
+	}
+
+	line := style.Render(plain)
+	if badge := m.gitBadge(ti.item.Path); badge != "" {
+		line += " " + m.theme.git.Render(badge)
+	}
+	if badge := m.statusBadge(ti.item.Path); badge != "" {
+		line += " " + m.statusStyle(ti.item.Path).Render(badge)
+	}
+	return ansi.Truncate(line, maxWidth, "…")
+}
+
 // viewRightPane renders the projects tree (right pane in desktop mode)
 func (m model) viewRightPane(width, height int) string {
 	var lines []string
@@ -611,24 +874,7 @@ func (m model) viewRightPane(width, height int) string {
 	if len(m.projectTreeItems) == 0 {
 		lines = append(lines, "(no projects)")
 	} else {
-		for i, ti := range m.projectTreeItems {
-			selected := m.selectedItems[ti.item.Path]
-			expanded := m.projectExpanded[ti.item.Path]
-			line := renderTreeItem(ti, m.projectCursor, i, selected, expanded)
-
-			// GOLDEN RULE #2: Truncate to prevent wrapping
-			maxWidth := width - 4 // Account for padding
-			if len(line) > maxWidth {
-				line = line[:maxWidth-1] + "…"
-			}
-
-			// Highlight cursor
-			if i == m.projectCursor && m.activePane == paneProject {
-				line = selectedStyle.Render(line)
-			}
-
-			lines = append(lines, line)
-		}
+		lines = append(lines, m.treeViewport(m.projectTreeItems, m.projectCursor, m.projectOffset, m.activePane == paneProject, m.projectExpanded, width, height-2)...)
 	}
 
 	// Fill to exact height
@@ -646,30 +892,22 @@ func (m model) viewRightPane(width, height int) string {
 
 // viewInfoPane renders the info/help pane (bottom pane)
 func (m model) viewInfoPane(width, height int) string {
-	var lines []string
+	body := m.infoBodyLines(width)
 
-	// Add title
-	lines = append(lines, "Info")
-	lines = append(lines, "")
+	// Scroll the body; the title stays put
+	visible := height - 2
+	offset := clampOffset(m.infoOffset, len(body), visible)
+	if offset > 0 {
+		body = body[offset:]
+	}
 
-	// Show info content or help text
-	if m.infoContent != "" {
-		// Split content into lines
-		contentLines := strings.Split(m.infoContent, "\n")
-		for _, line := range contentLines {
-			// GOLDEN RULE #2: Truncate to prevent wrapping
-			maxWidth := width - 4
-			if len(line) > maxWidth {
-				line = line[:maxWidth-1] + "…"
-			}
-			lines = append(lines, line)
-		}
-	} else {
-		// Default help text
-		lines = append(lines, "Navigate with arrows or vim keys")
-		lines = append(lines, "Space: expand/select  Enter: launch  Tab: switch panes")
-		lines = append(lines, "t: toggle mode  c: clear  e: edit config  q: quit")
+	// Add title
+	title := "Info"
+	if len(body)+offset > visible && visible > 0 {
+		title += fmt.Sprintf(" (%d/%d, %s)", offset+1, len(body)+offset, m.keys.InfoUp.Help().Key+"/"+m.keys.InfoDown.Help().Key)
 	}
+	lines := []string{title, ""}
+	lines = append(lines, body...)
 
 	// Fill to exact height
 	for len(lines) < height {
@@ -684,24 +922,84 @@ func (m model) viewInfoPane(width, height int) string {
 	return strings.Join(lines, "\n")
 }
 
+// infoBodyLines builds the scrollable part of the info pane
+func (m model) infoBodyLines(width int) []string {
+	// Launch progress sits above the item info while visible
+	lines := m.viewLaunchProgress()
+
+	// Show info content or help text
+	if m.infoContent != "" {
+		// GOLDEN RULE #2: Wrap ourselves so the terminal never does
+		// Long commands and plan steps continue indented on the next lines
+		maxWidth := width - 4
+		for _, line := range strings.Split(m.infoContent, "\n") {
+			if lipgloss.Width(line) <= maxWidth {
+				lines = append(lines, line)
+				continue
+			}
+			trimmed := strings.TrimLeft(line, " ")
+			indent := line[:len(line)-len(trimmed)]
+			lines = append(lines, wrapText(trimmed, maxWidth, indent, indent+"  ")...)
+		}
+		// Docs are wrapped (not truncated) to the pane width
+		lines = append(lines, m.docLines(width-4)...)
+	} else {
+		// Default help text, from the active key bindings
+		// GOLDEN RULE #2: Wrap to prevent the border from wrapping it
+		for _, line := range m.helpLines(m.getLayoutMode()) {
+			lines = append(lines, wrapText(line, width-4, "", "  ")...)
+		}
+	}
+
+	return lines
+}
+
+// infoViewport returns the info pane's content width and scrollable height
+func (m model) infoViewport() (int, int) {
+	_, _, treeHeight, infoHeight := m.calculateLayout()
+	if m.getLayoutMode() == layoutMobile {
+		infoHeight = treeHeight // Info replaces the tree when toggled
+	}
+	return m.width - 2, infoHeight - 2
+}
+
+// scrollInfo moves the info pane's scroll position, clamped to its content
+func (m *model) scrollInfo(delta int) {
+	width, height := m.infoViewport()
+	m.infoOffset = clampOffset(m.infoOffset+delta, len(m.infoBodyLines(width)), height)
+}
+
+// clampOffset keeps a scroll offset within content of total lines shown visible at a time
+func clampOffset(offset, total, visible int) int {
+	if offset > total-visible {
+		offset = total - visible
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return offset
+}
+
 // viewCombinedTree renders a combined tree for compact/mobile modes
 func (m model) viewCombinedTree(width, height int) string {
 	var lines []string
 
 	// Determine which pane to show in compact mode
 	var items []launchTreeItem
-	var cursor int
+	var cursor, offset int
 	var expanded map[string]bool
 	var title string
 
 	if m.showingProjects {
 		items = m.projectTreeItems
 		cursor = m.projectCursor
+		offset = m.projectOffset
 		expanded = m.projectExpanded
 		title = "Projects"
 	} else {
 		items = m.globalTreeItems
 		cursor = m.globalCursor
+		offset = m.globalOffset
 		expanded = m.globalExpanded
 		title = "Global Tools"
 	}
@@ -714,24 +1012,7 @@ func (m model) viewCombinedTree(width, height int) string {
 	if len(items) == 0 {
 		lines = append(lines, "(no items)")
 	} else {
-		for i, ti := range items {
-			selected := m.selectedItems[ti.item.Path]
-			isExpanded := expanded[ti.item.Path]
-			line := renderTreeItem(ti, cursor, i, selected, isExpanded)
-
-			// GOLDEN RULE #2: Truncate to prevent wrapping
-			maxWidth := width - 4
-			if len(line) > maxWidth {
-				line = line[:maxWidth-1] + "…"
-			}
-
-			// Highlight cursor
-			if i == cursor {
-				line = selectedStyle.Render(line)
-			}
-
-			lines = append(lines, line)
-		}
+		lines = append(lines, m.treeViewport(items, cursor, offset, true, expanded, width, height-2)...)
 	}
 
 	// Fill to exact height
@@ -757,6 +1038,34 @@ func (m model) View() string {
 		return "Error: " + m.err.Error() + "\n\nPress q to quit.\n"
 	}
 
+	if m.showErrorLog {
+		return m.viewErrorLog()
+	}
+
+	if m.showHelp {
+		return m.viewHelp()
+	}
+
+	if m.showPalette {
+		return m.viewPalette()
+	}
+
+	if m.showSpawnDialog {
+		return m.viewSpawnDialog()
+	}
+
+	if m.showWorktreePrompt {
+		return m.viewWorktreePrompt()
+	}
+
+	if m.showAIPrompt {
+		return m.viewAIPrompt()
+	}
+
+	if m.showImportPrompt {
+		return m.viewImportPrompt()
+	}
+
 	var sb strings.Builder
 
 	// Header (3 lines total)
@@ -787,16 +1096,19 @@ func (m model) View() string {
 	} else {
 		sb.WriteString("Direct")
 	}
+	if m.dryRun {
+		sb.WriteString(" (dry run)")
+	}
+	sb.WriteString(" | Target: " + m.spawnTarget.String())
 	sb.WriteString("\n\n")
 
 	// Get layout dimensions
 	leftWidth, rightWidth, treeHeight, infoHeight := m.calculateLayout()
 	mode = m.getLayoutMode()
 
-	// Define border style
-	borderStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240"))
+	// Border styles: the focused pane is highlighted (the tree, when it's the only one)
+	borderStyle := m.theme.border
+	focusedStyle := m.theme.activeBorder
 
 	switch mode {
 	case layoutDesktop:
@@ -804,8 +1116,8 @@ func (m model) View() string {
 		leftContent := m.viewLeftPane(leftWidth-2, treeHeight)   // -2 for borders
 		rightContent := m.viewRightPane(rightWidth-2, treeHeight) // -2 for borders
 
-		leftPane := borderStyle.Width(leftWidth - 2).Render(leftContent)
-		rightPane := borderStyle.Width(rightWidth - 2).Render(rightContent)
+		leftPane := m.theme.paneBorder(m.activePane == paneGlobal).Width(leftWidth - 2).Render(leftContent)
+		rightPane := m.theme.paneBorder(m.activePane == paneProject).Width(rightWidth - 2).Render(rightContent)
 
 		// Join left and right panes horizontally
 		topRow := lipgloss.JoinHorizontal(lipgloss.Top, leftPane, rightPane)
@@ -822,7 +1134,7 @@ func (m model) View() string {
 	case layoutCompact:
 		// 2-pane layout: Combined tree (top), Info (bottom)
 		treeContent := m.viewCombinedTree(m.width-2, treeHeight)
-		treePane := borderStyle.Width(m.width - 2).Render(treeContent)
+		treePane := focusedStyle.Width(m.width - 2).Render(treeContent)
 		sb.WriteString(treePane)
 		sb.WriteString("\n")
 
@@ -843,34 +1155,30 @@ func (m model) View() string {
 		} else {
 			// Show tree
 			treeContent := m.viewCombinedTree(m.width-2, treeHeight)
-			treePane := borderStyle.Width(m.width - 2).Render(treeContent)
+			treePane := focusedStyle.Width(m.width - 2).Render(treeContent)
 			sb.WriteString(treePane)
 		}
 	}
 
 	// Status line
 	sb.WriteString("\n")
+	status := ""
 	if len(m.selectedItems) > 0 {
-		sb.WriteString(fmt.Sprintf("Selected: %d items | ", len(m.selectedItems)))
+		status = fmt.Sprintf("Selected: %d items | ", len(m.selectedItems))
 	}
+	sb.WriteString(status)
 
 	// Footer (adapts to layout mode) - static, no scrolling to prevent flashing
-	var footerText string
-	switch mode {
-	case layoutDesktop:
-		footerText = "↑/↓: nav  Tab: panes  Space: expand/select  Enter: launch  e: edit  c: clear  q: quit"
-	case layoutCompact:
-		footerText = "↑/↓: nav  Tab: switch  Space: select  Enter: launch  e: edit  c: clear  q: quit"
-	case layoutMobile:
-		footerText = "↑/↓: nav  Tab: switch  i: info  Space: select  Enter: launch  q: quit"
-	}
+	// Generated from the active key bindings; the help model truncates it to the width
+	m.help.Width = m.width - 2 - len(status)
+	footerText := m.help.ShortHelpView(m.footerBindings(mode))
 
-	// Truncate footer if needed (no scrolling)
-	if len(footerText) > m.width-2 {
-		footerText = footerText[:m.width-5] + "..."
+	// A toast temporarily replaces the footer
+	if m.toastText != "" {
+		footerText = m.toastLine(m.help.Width)
 	}
+	// No trailing newline: the view must be exactly m.height lines or the header scrolls off
 	sb.WriteString(footerText)
-	sb.WriteString("\n")
 
 	return sb.String()
 }
@@ -928,6 +1236,9 @@ func loadConfig() tea.Msg {
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return configLoadedMsg{err: err}
 	}
+	if err := resolveIncludes(&config, configPath); err != nil {
+		return configLoadedMsg{err: err}
+	}
 
 	return configLoadedMsg{
 		config: config,
@@ -949,6 +1260,8 @@ func detectTerminal() terminalType {
 		return terminalKitty
 	case term == "xterm-256color":
 		return terminalXterm
+	case term == "linux" || term == "dumb" || strings.HasPrefix(term, "vt"):
+		return terminalConsole
 	case os.Getenv("PREFIX") == "/data/data/com.termux/files/usr":
 		return terminalTermux
 	case os.Getenv("WT_SESSION") != "":
@@ -988,6 +1301,12 @@ func runCommandDirectly(item launchItem) tea.Cmd {
 			os.Exit(1)
 		}
 
+		// After hooks (before hooks already ran inside the TUI)
+		if err := runHooks("after", collectHooks([]launchItem{item}, "after")); err != nil {
+			fmt.Printf("%v\n", err)
+			os.Exit(1)
+		}
+
 		os.Exit(0)
 		return nil
 	}
@@ -1092,15 +1411,33 @@ func (m *model) updateInfoPane() {
 
 	if !isValid {
 		m.infoContent = ""
+		m.infoPath = ""
+		m.currentInfo = paneInfo{}
 		return
 	}
 
+	// A different item starts at the top of its info
+	if currentItem.Path != m.infoPath {
+		m.infoPath = currentItem.Path
+		m.infoOffset = 0
+	}
+	m.currentInfo = paneInfo{
+		description: currentItem.Description,
+		repo:        currentItem.Repo,
+	}
+	if currentItem.InfoFile != "" {
+		m.currentInfo.mdPath = infoFilePath(currentItem)
+	}
+	if doc := m.docs[currentItem.Path]; !doc.markdown {
+		m.currentInfo.cliFlags = doc.text
+	}
+
 	// Build info content based on item type
 	var info strings.Builder
 
 	// Name and icon
-	if currentItem.Icon != "" {
-		info.WriteString(currentItem.Icon + " ")
+	if icon := m.icons.item(currentItem.Icon); icon != "" {
+		info.WriteString(icon + " ")
 	}
 	info.WriteString(currentItem.Name + "\n")
 	info.WriteString(strings.Repeat("─", len(currentItem.Name)+2) + "\n\n")
@@ -1110,10 +1447,16 @@ func (m *model) updateInfoPane() {
 	case typeCategory:
 		info.WriteString(fmt.Sprintf("Type: Category\n"))
 		info.WriteString(fmt.Sprintf("Children: %d items\n", len(currentItem.Children)))
+		if currentItem.IsWorktree {
+			info.WriteString(fmt.Sprintf("Worktree: %s\n", currentItem.Name))
+		}
 		if currentItem.Cwd != "" {
 			info.WriteString(fmt.Sprintf("\n📂 Project Directory:\n%s\n", currentItem.Cwd))
-			info.WriteString("\n💡 Press Enter to CD into this project\n")
+			if m.keys.Launch.Enabled() {
+				info.WriteString("\n💡 Press " + m.keys.Launch.Help().Key + " to CD into this project\n")
+			}
 		}
+		writeGitInfo(&info, m.gitStatus[currentItem.Path], keyHint(m.keys.NewWorktree, "new worktree"))
 
 	case typeCommand:
 		info.WriteString(fmt.Sprintf("Type: Command\n"))
@@ -1126,22 +1469,94 @@ func (m *model) updateInfoPane() {
 		if currentItem.SpawnStr != "" {
 			info.WriteString(fmt.Sprintf("Spawn Mode: %s\n", currentItem.SpawnStr))
 		}
+		if currentItem.RunAsStr != "" {
+			info.WriteString(fmt.Sprintf("Run As: %s\n", currentItem.RunAs))
+		}
+		if currentItem.IsAI {
+			writeAIInfo(&info, m.contextItem(currentItem, promptAsk), currentItem.Prompt)
+		}
+		writeHooksInfo(&info, currentItem)
 
 	case typeProfile:
 		info.WriteString(fmt.Sprintf("Type: Profile\n"))
 		info.WriteString(fmt.Sprintf("Layout: %s\n", currentItem.LayoutStr))
 		info.WriteString(fmt.Sprintf("Panes: %d\n", len(currentItem.Panes)))
+		if currentItem.SessionName != "" {
+			info.WriteString(fmt.Sprintf("Session: %s (on exists: %s)\n", currentItem.SessionName, currentItem.OnExists))
+		}
+		if currentItem.TargetStr != "" {
+			info.WriteString(fmt.Sprintf("Target: %s\n", currentItem.Target))
+		}
+		if currentItem.RunAsStr != "" {
+			info.WriteString(fmt.Sprintf("Run As: %s\n", currentItem.RunAs))
+		}
 		if len(currentItem.Panes) > 0 {
 			info.WriteString("\nPane Commands:\n")
 			for i, pane := range currentItem.Panes {
 				info.WriteString(fmt.Sprintf("  %d. %s\n", i+1, pane.Command))
 			}
 		}
+		writeHooksInfo(&info, currentItem)
+	}
+
+	// Process status for launched commands/profiles
+	if tracked := m.tracked[currentItem.Path]; len(tracked) > 0 {
+		info.WriteString("\nProcesses:\n")
+		for _, tp := range tracked {
+			where := tp.paneID
+			if tp.proc != nil {
+				where = fmt.Sprintf("pid %d", tp.proc.Pid)
+			}
+			status := tp.state.String()
+			if (tp.state == stateExited || tp.state == stateCrashed) && tp.exitCode >= 0 {
+				status = fmt.Sprintf("%s(%d)", status, tp.exitCode)
+			}
+			if tp.detail != "" && tp.state != stateRunning {
+				status += " - " + tp.detail
+			}
+			info.WriteString(fmt.Sprintf("  %s: %s\n", where, status))
+		}
+		if hints := joinHints(keyHint(m.keys.Focus, "focus"), keyHint(m.keys.Restart, "restart"), keyHint(m.keys.Kill, "kill")); hints != "" {
+			info.WriteString("\n💡 " + hints + "\n")
+		}
 	}
 
 	m.infoContent = info.String()
 }
 
+// writeHooksInfo lists an item's before/after hooks in the info pane
+func writeHooksInfo(info *strings.Builder, item launchItem) {
+	if len(item.Before) > 0 {
+		info.WriteString("\nBefore:\n")
+		for _, hook := range item.Before {
+			info.WriteString("  $ " + hook + "\n")
+		}
+	}
+	if len(item.After) > 0 {
+		info.WriteString("\nAfter:\n")
+		for _, hook := range item.After {
+			info.WriteString("  $ " + hook + "\n")
+		}
+	}
+}
+
+// currentItem returns the item under the cursor in the focused pane
+func (m model) currentItem() (launchItem, bool) {
+	showProjects := m.showingProjects
+	if m.getLayoutMode() == layoutDesktop {
+		showProjects = m.activePane == paneProject
+	}
+
+	if showProjects {
+		if m.projectCursor < len(m.projectTreeItems) {
+			return m.projectTreeItems[m.projectCursor].item, true
+		}
+	} else if m.globalCursor < len(m.globalTreeItems) {
+		return m.globalTreeItems[m.globalCursor].item, true
+	}
+	return launchItem{}, false
+}
+
 // getLayoutMode determines which responsive layout to use based on terminal size
 func (m model) getLayoutMode() layoutMode {
 	// Mobile mode: Very small height (Termux with keyboard open)
@@ -1170,8 +1585,8 @@ func (m model) calculateLayout() (int, int, int, int) {
 	// Subtract header (3 lines: title + cwd + blank)
 	contentHeight -= 3
 
-	// Subtract footer area (2 lines: status line + footer)
-	contentHeight -= 2
+	// Subtract footer line (selection status + key help)
+	contentHeight -= 1
 
 	// GOLDEN RULE #1: Account for borders BEFORE rendering
 	// Subtract 2 for panel borders (top + bottom)
@@ -1183,6 +1598,9 @@ func (m model) calculateLayout() (int, int, int, int) {
 	switch mode {
 	case layoutDesktop:
 		// 3-pane: Left | Right | Bottom
+		// The info pane below the trees has its own borders
+		contentHeight -= 2
+
 		// Split width proportionally (50/50)
 		leftWidth := contentWidth / 2
 		rightWidth := contentWidth - leftWidth
@@ -1196,6 +1614,7 @@ func (m model) calculateLayout() (int, int, int, int) {
 	case layoutCompact:
 		// 2-pane: Combined tree on top | Info on bottom
 		// Use full width for tree
+		contentHeight -= 2 // Info pane borders
 		treeHeight := (contentHeight * 3) / 4
 		infoHeight := contentHeight - treeHeight
 
@@ -1208,6 +1627,7 @@ func (m model) calculateLayout() (int, int, int, int) {
 
 	default:
 		// Fallback to desktop
+		contentHeight -= 2
 		leftWidth := contentWidth / 2
 		rightWidth := contentWidth - leftWidth
 		treeHeight := (contentHeight * 2) / 3
@@ -1216,17 +1636,6 @@ func (m model) calculateLayout() (int, int, int, int) {
 	}
 }
 
-// writeCDTarget writes the target directory to a file so the shell wrapper can cd after exit
-func writeCDTarget(path string) error {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return err
-	}
-
-	targetFile := filepath.Join(homeDir, ".tui-launcher_cd_target")
-	return os.WriteFile(targetFile, []byte(path), 0644)
-}
-
 // editConfigInTmux opens the config file in a tmux split
 func editConfigInTmux() tea.Cmd {
 	return func() tea.Msg {
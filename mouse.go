@@ -0,0 +1,119 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// mouse.go - Clicking tree rows
+// A click moves the cursor to the row under it; clicking a category's
+// expand glyph toggles it, clicking a checkbox toggles the selection, and a
+// double-click launches like Enter. Taps on Termux arrive as the same clicks
+
+// doubleClickTime is how close two clicks on the same row must be to launch
+const doubleClickTime = 400 * time.Millisecond
+
+// treeAt maps a screen position to a tree row: the tree, the item index and the column in the row
+func (m model) treeAt(x, y int) (project bool, index, col int, ok bool) {
+	mode := m.getLayoutMode()
+	if mode == layoutMobile && m.showingInfo {
+		return false, 0, 0, false
+	}
+
+	// Header (3 lines), the pane's top border, its title and a blank line come first
+	leftWidth, _, _, _ := m.calculateLayout()
+	row := y - 3 - 1 - 2
+	if row < 0 || row >= m.treeRows() {
+		return false, 0, 0, false
+	}
+
+	// GOLDEN RULE #3: Match mouse detection to layout (X for horizontal split)
+	project, left := m.showingProjects, 0
+	if mode == layoutDesktop {
+		project = x >= leftWidth
+		if project {
+			left = leftWidth
+		}
+	}
+	col = x - left - 1 // Left border
+
+	items, offset := m.globalTreeItems, m.globalOffset
+	if project {
+		items, offset = m.projectTreeItems, m.projectOffset
+	}
+	index = offset + row
+	if col < 0 || index >= len(items) {
+		return false, 0, 0, false
+	}
+	return project, index, col, true
+}
+
+// treeRowPart finds what a click at col hits in a rendered tree row
+// Rows start with the 2-column cursor indicator and the tree lines; the space
+// after the checkbox or glyph counts too, which makes it easier to tap
+func treeRowPart(ti launchTreeItem, col int, selected, expanded bool, icons iconSet) clickPart {
+	marker := treeMarker(ti, selected, expanded, icons)
+	if marker == "" {
+		return clickRow
+	}
+	start := 2 + lipgloss.Width(treeIndent(ti, icons))
+	if col < start || col > start+lipgloss.Width(marker) {
+		return clickRow
+	}
+	if ti.item.ItemType == typeCategory {
+		return clickToggle
+	}
+	return clickCheckbox
+}
+
+// clickTree handles a left click in a tree pane
+// It reports whether the click was the second of a double-click, which launches
+func (m *model) clickTree(msg tea.MouseMsg) bool {
+	project, index, col, ok := m.treeAt(msg.X, msg.Y)
+	if !ok {
+		m.lastClickPath = ""
+		return false
+	}
+
+	ti := m.globalTreeItems[index]
+	expanded := m.globalExpanded
+	if project {
+		ti = m.projectTreeItems[index]
+		expanded = m.projectExpanded
+		m.projectCursor = index
+	} else {
+		m.globalCursor = index
+	}
+	path := ti.item.Path
+	m.updateInfoPane()
+
+	switch treeRowPart(ti, col, m.selectedItems[path], expanded[path], m.icons) {
+	case clickToggle:
+		m.lastClickPath = ""
+		expanded[path] = !expanded[path]
+		if project {
+			m.projectTreeItems = flattenTree(m.projectItems, m.projectExpanded)
+		} else {
+			m.globalTreeItems = flattenTree(m.globalItems, m.globalExpanded)
+		}
+		return false
+
+	case clickCheckbox:
+		m.lastClickPath = ""
+		if m.selectedItems[path] {
+			delete(m.selectedItems, path)
+		} else {
+			m.selectedItems[path] = true
+		}
+		return false
+	}
+
+	if m.lastClickPath == path && time.Since(m.lastClickAt) < doubleClickTime {
+		m.lastClickPath = ""
+		return true
+	}
+	m.lastClickPath, m.lastClickAt = path, time.Now()
+	return false
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// palette.go - Command palette and help overlay
+// The palette fuzzy-searches actions and items; picking an action runs it as if
+// its key was pressed, picking an item moves the cursor to it
+
+// openPalette shows an empty command palette
+func (m *model) openPalette() {
+	m.showPalette = true
+	m.paletteQuery = ""
+	m.paletteCursor = 0
+}
+
+// paletteEntries returns actions and items matching the palette query, best first
+func (m model) paletteEntries() []paletteEntry {
+	var entries []paletteEntry
+
+	for _, action := range keyActions {
+		binding := action.binding(&m.keys)
+		if action.title == "" || !binding.Enabled() {
+			continue
+		}
+		entries = append(entries, paletteEntry{title: action.title, hint: binding.Help().Key, action: action.name})
+	}
+
+	var addItems func(items []launchItem, project bool)
+	addItems = func(items []launchItem, project bool) {
+		for _, item := range items {
+			entries = append(entries, paletteEntry{title: item.Name, hint: item.Path, item: item, project: project})
+			addItems(item.Children, project)
+		}
+	}
+	addItems(m.globalItems, false)
+	addItems(m.projectItems, true)
+
+	query := strings.TrimSpace(m.paletteQuery)
+	if query == "" {
+		return entries
+	}
+
+	// Titles match best; a match anywhere in an item's path counts for less
+	var matches []paletteEntry
+	for _, entry := range entries {
+		if score, ok := fuzzyScore(query, entry.title); ok {
+			entry.score = score + 100
+		} else if score, ok := fuzzyScore(query, entry.hint); ok && entry.action == "" {
+			entry.score = score
+		} else {
+			continue
+		}
+		matches = append(matches, entry)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	return matches
+}
+
+// fuzzyScore matches query as a case-insensitive subsequence of text
+// Consecutive characters and characters at word starts score higher
+func fuzzyScore(query, text string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	t := []rune(text)
+	score, qi, streak := 0, 0, 0
+
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if q[qi] == ' ' {
+			qi++ // Spaces in the query only separate words
+			ti--
+			continue
+		}
+		if unicode.ToLower(t[ti]) != q[qi] {
+			streak = 0
+			continue
+		}
+		score++
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 5
+		}
+		streak++
+		score += streak * 2
+		qi++
+	}
+	for qi < len(q) && q[qi] == ' ' {
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	// Shorter texts are closer matches
+	return score*10 - len(t), true
+}
+
+// updatePalette handles keys while the command palette is open
+func (m model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	entries := m.paletteEntries()
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.showPalette = false
+
+	case "up", "shift+tab":
+		if m.paletteCursor > 0 {
+			m.paletteCursor--
+		}
+
+	case "down", "tab":
+		if m.paletteCursor < len(entries)-1 {
+			m.paletteCursor++
+		}
+
+	case "enter":
+		if m.paletteCursor >= len(entries) {
+			return m, nil
+		}
+		m.showPalette = false
+		entry := entries[m.paletteCursor]
+		if entry.action == "" {
+			m.revealItem(entry.item, entry.project)
+			return m, m.requestDoc()
+		}
+		return m.runAction(entry.action)
+
+	default:
+		if m.keys.Palette.Enabled() && msg.String() == m.keys.Palette.Keys()[0] {
+			m.showPalette = false
+			return m, nil
+		}
+		m.paletteQuery = editText(m.paletteQuery, msg)
+		m.paletteCursor = 0
+	}
+	return m, nil
+}
+
+// runAction runs an action by replaying its first key through Update
+func (m model) runAction(name string) (tea.Model, tea.Cmd) {
+	action, ok := findKeyAction(name)
+	if !ok {
+		return m, nil
+	}
+	binding := action.binding(&m.keys)
+	msg, ok := keyMsgFor(binding.Keys()[0])
+	if !ok {
+		return m, m.showToast(fmt.Sprintf("✗ Can't run %q from the palette", binding.Keys()[0]), true)
+	}
+	return m.Update(msg)
+}
+
+// keyMsgFor builds the key press Bubble Tea reports as name (e.g. "ctrl+r", "alt+x", "L")
+func keyMsgFor(name string) (tea.KeyMsg, bool) {
+	alt := false
+	if strings.HasPrefix(name, "alt+") && len(name) > len("alt+") {
+		alt, name = true, strings.TrimPrefix(name, "alt+")
+	}
+
+	// Named keys first (" " is the space key)
+	for t := tea.KeyType(-128); t < 128; t++ {
+		if t != tea.KeyRunes && t.String() == name {
+			return tea.KeyMsg{Type: t, Alt: alt}, true
+		}
+	}
+	if runes := []rune(name); len(runes) == 1 {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: runes, Alt: alt}, true
+	}
+	return tea.KeyMsg{}, false
+}
+
+// revealItem moves the cursor to an item, expanding its parents and switching panes
+func (m *model) revealItem(item launchItem, project bool) {
+	expanded := m.globalExpanded
+	if project {
+		expanded = m.projectExpanded
+	}
+	parts := strings.Split(item.Path, "/")
+	for i := 1; i < len(parts); i++ {
+		expanded[strings.Join(parts[:i], "/")] = true
+	}
+
+	if project {
+		m.activePane, m.showingProjects = paneProject, true
+		m.projectTreeItems = flattenTree(m.projectItems, m.projectExpanded)
+		m.selectProjectItem(item.Path)
+	} else {
+		m.activePane, m.showingProjects = paneGlobal, false
+		m.globalTreeItems = flattenTree(m.globalItems, m.globalExpanded)
+		for i, ti := range m.globalTreeItems {
+			if ti.item.Path == item.Path {
+				m.globalCursor = i
+			}
+		}
+	}
+	m.updateInfoPane()
+}
+
+// viewPalette renders the command palette centered over the screen
+func (m model) viewPalette() string {
+	entries := m.paletteEntries()
+	width := min(max(m.width-6, 20), 72)
+	rows := max(m.height-8, 3)
+
+	// Keep the cursor in view
+	start := 0
+	if m.paletteCursor >= rows {
+		start = m.paletteCursor - rows + 1
+	}
+
+	lines := []string{
+		m.theme.heading.Render("> ") + m.paletteQuery + "█",
+		"",
+	}
+	for i := start; i < len(entries) && i < start+rows; i++ {
+		entry := entries[i]
+		hint := entry.hint
+		if icon := m.icons.item(entry.item.Icon); entry.action == "" && icon != "" {
+			hint = icon + " " + hint
+		}
+		title := truncateText(entry.title, width-2)
+		hint = truncateText(hint, max(width-lipgloss.Width(title)-4, 1))
+		gap := strings.Repeat(" ", max(width-lipgloss.Width(title)-lipgloss.Width(hint)-2, 1))
+
+		if i == m.paletteCursor {
+			lines = append(lines, m.theme.cursor.Render("▶ "+title)+gap+hint)
+		} else {
+			lines = append(lines, "  "+title+gap+m.theme.muted.Render(hint))
+		}
+	}
+	if len(entries) == 0 {
+		lines = append(lines, "  (no matches)")
+	}
+	lines = append(lines, "", m.theme.muted.Render("↑/↓: choose  Enter: run / go to item  Esc: close"))
+
+	box := lipgloss.NewStyle().
+		Border(m.icons.border).
+		BorderForeground(m.theme.activeBorder.GetBorderTopForeground()).
+		Padding(0, 1).
+		Width(width + 2).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Top, box)
+}
+
+// helpOverlayLines lists every binding of the current layout mode, grouped
+func (m model) helpOverlayLines() []string {
+	mode := m.getLayoutMode()
+	heading := m.theme.heading
+
+	keyWidth := 0
+	groups := m.keys.groups(mode == layoutMobile)
+	for _, group := range groups {
+		for _, binding := range group.bindings {
+			keyWidth = max(keyWidth, lipgloss.Width(binding.Help().Key))
+		}
+	}
+
+	var lines []string
+	for _, group := range groups {
+		var entries []string
+		for _, binding := range group.bindings {
+			if binding.Enabled() {
+				help := binding.Help()
+				entries = append(entries, fmt.Sprintf("  %-*s  %s", keyWidth, help.Key, help.Desc))
+			}
+		}
+		if len(entries) > 0 {
+			lines = append(lines, heading.Render(group.title))
+			lines = append(lines, entries...)
+			lines = append(lines, "")
+		}
+	}
+
+	lines = append(lines, heading.Render("Always"))
+	lines = append(lines, fmt.Sprintf("  %-*s  %s", keyWidth, "ctrl+c", "quit"))
+	lines = append(lines, "")
+	lines = append(lines, heading.Render("Dialogs"))
+	lines = append(lines, "  Spawn dialog: "+m.spawnDialogHints())
+	lines = append(lines, "  Palette: type to search  ↑/↓: choose  enter: run  esc: close")
+	lines = append(lines, "  Error log: "+m.errorLogHints())
+	return lines
+}
+
+// viewHelp renders the full-screen help overlay
+func (m model) viewHelp() string {
+	var sb strings.Builder
+	header := fmt.Sprintf("Key bindings (%s layout)", m.getLayoutMode())
+	sb.WriteString(joinHints(header, keyHint(m.keys.nav(), "scroll"), keyHint(firstKeys(m.keys.Help, m.keys.Dismiss, m.keys.Quit), "close")) + "\n\n")
+
+	lines := m.helpOverlayLines()
+	height := max(m.height-3, 1)
+	start := clampOffset(m.helpOffset, len(lines), height)
+	end := min(start+height, len(lines))
+
+	for _, line := range lines[start:end] {
+		// GOLDEN RULE #2: Truncate to prevent wrapping
+		sb.WriteString(truncateText(line, max(m.width-1, 1)) + "\n")
+	}
+	return sb.String()
+}
+
+// updateHelp handles keys while the help overlay is open
+func (m model) updateHelp(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	height := max(m.height-3, 1)
+	total := len(m.helpOverlayLines())
+
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Help, m.keys.Dismiss, m.keys.Quit):
+		m.showHelp = false
+	case key.Matches(msg, m.keys.Up):
+		m.helpOffset = clampOffset(m.helpOffset-1, total, height)
+	case key.Matches(msg, m.keys.Down):
+		m.helpOffset = clampOffset(m.helpOffset+1, total, height)
+	case key.Matches(msg, m.keys.PageUp):
+		m.helpOffset = clampOffset(m.helpOffset-height, total, height)
+	case key.Matches(msg, m.keys.PageDown):
+		m.helpOffset = clampOffset(m.helpOffset+height, total, height)
+	}
+	return m, nil
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// plan.go - Dry runs: the exact invocations a launch would make, without running them
+// A dry-run spawner walks the same code as a real launch, recording each tmux/xterm
+// call. Ids tmux would print (-P -F) become shell variables, so the plan doubles
+// as a standalone script
+
+// planFormatVars maps tmux -F fields to placeholder variable prefixes
+var planFormatVars = map[string]string{
+	"#{window_id}": "W",
+	"#{pane_id}":   "P",
+}
+
+// placeholderPattern matches a placeholder id produced by recordTmux
+var placeholderPattern = regexp.MustCompile(`^\$\{[A-Z][0-9]+\}$`)
+
+// planLaunch computes what a launch would run, including its before/after hooks
+func planLaunch(title string, hookItems []launchItem, run func(s *spawner) spawnCompleteMsg) *spawnPlan {
+	s := &spawner{dryRun: true}
+	if insideTmux() {
+		s.note("inside tmux, launcher pane " + os.Getenv("TMUX_PANE"))
+	} else {
+		s.note("outside tmux")
+	}
+
+	plan := &spawnPlan{title: title}
+	s.recordHooks("before", collectHooks(hookItems, "before"))
+	if msg := run(s); msg.err != nil {
+		plan.err = msg.err
+	} else {
+		s.recordHooks("after", collectHooks(hookItems, "after"))
+	}
+	plan.steps = s.steps
+	return plan
+}
+
+// planDirect computes a direct-mode (non-tmux) launch: the command runs in this terminal
+func planDirect(item launchItem) *spawnPlan {
+	s := &spawner{dryRun: true}
+	s.recordHooks("before", collectHooks([]launchItem{item}, "before"))
+	s.note("runs in the launcher's terminal")
+	s.record(planStep{args: []string{"sh", "-c", item.Command}, dir: item.Cwd})
+	s.recordHooks("after", collectHooks([]launchItem{item}, "after"))
+	return &spawnPlan{title: item.Name, steps: s.steps}
+}
+
+// planCmd computes a plan off the UI thread (it may query tmux for existing sessions)
+func planCmd(compute func() *spawnPlan) tea.Cmd {
+	return func() tea.Msg {
+		return planMsg{plan: compute()}
+	}
+}
+
+// printPlan loads the config and prints the plan for one item (by name or path)
+// Items launch as the TUI would by default: tmux mode, auto spawn target
+func printPlan(query string, asScript bool) error {
+	loaded := loadConfig().(configLoadedMsg)
+	if loaded.err != nil {
+		return loaded.err
+	}
+
+	globalItems, projectItems := buildTreeFromConfig(loaded.config)
+	item, ok := findItem(append(globalItems, projectItems...), query)
+	if !ok {
+		return fmt.Errorf("no item named %q", query)
+	}
+
+	var plan *spawnPlan
+	switch item.ItemType {
+	case typeCommand:
+		plan = planLaunch(item.Name, []launchItem{item}, func(s *spawner) spawnCompleteMsg {
+			return s.single(item, item.DefaultSpawn)
+		})
+	case typeProfile:
+		items, opts := profileItems(item), profileOptions(item, targetAuto)
+		plan = planLaunch(item.Name, []launchItem{item}, func(s *spawner) spawnCompleteMsg {
+			return s.multiple(items, item.Layout, opts)
+		})
+	default:
+		return fmt.Errorf("%s is a %s; only commands and profiles can be launched", item.Path, item.ItemType)
+	}
+
+	if asScript {
+		fmt.Print(plan.script())
+	} else {
+		fmt.Println(strings.Join(plan.lines(), "\n"))
+	}
+	return plan.err
+}
+
+// findItem searches the tree for an item whose path or name matches query
+func findItem(items []launchItem, query string) (launchItem, bool) {
+	for _, item := range items {
+		if item.Path == query || item.Name == query {
+			return item, true
+		}
+		if found, ok := findItem(item.Children, query); ok {
+			return found, true
+		}
+	}
+	return launchItem{}, false
+}
+
+// record appends a step to a dry run
+func (s *spawner) record(step planStep) {
+	s.steps = append(s.steps, step)
+}
+
+// note records a comment explaining the steps that follow
+func (s *spawner) note(text string) {
+	if s.dryRun {
+		s.record(planStep{note: text})
+	}
+}
+
+// recordTmux records a tmux call and fakes its output
+// Each id the call would print (-F, or display-message's format) becomes a
+// fresh variable like ${P3}, and later steps target the variable
+func (s *spawner) recordTmux(args []string) string {
+	format := ""
+	for i, arg := range args {
+		if arg == "-F" && i+1 < len(args) {
+			format = args[i+1]
+		}
+	}
+	if args[0] == "display-message" {
+		format = args[len(args)-1]
+	}
+
+	step := planStep{args: append([]string{"tmux"}, args...)}
+	var output []string
+	if format != "" {
+		s.nextID++
+		for _, field := range strings.Fields(format) {
+			prefix, ok := planFormatVars[field]
+			if !ok {
+				prefix = "X"
+			}
+			name := prefix + strconv.Itoa(s.nextID)
+			step.assign = append(step.assign, name)
+			output = append(output, "${"+name+"}")
+		}
+	}
+
+	s.record(step)
+	return strings.Join(output, " ")
+}
+
+// recordHooks records hook steps, which run with sh -c in their directory
+func (s *spawner) recordHooks(phase string, steps []hookStep) {
+	if len(steps) == 0 {
+		return
+	}
+	s.note(phase + " hooks")
+	for _, step := range steps {
+		s.record(planStep{args: []string{"sh", "-c", step.command}, dir: step.dir})
+	}
+}
+
+// planStages records depends_on staging in an order that satisfies every dependency
+// The stage runner polls in the background; a script waits inline instead
+func (s *spawner) planStages(paneIDs []string, items []launchItem) {
+	items = items[:len(paneIDs)]
+
+	names := make(map[string]int)
+	for i, item := range items {
+		names[item.Name] = i
+	}
+
+	started := make([]bool, len(items))
+	waited := make([]bool, len(items))
+	for i, item := range items {
+		started[i] = !isDeferred(item)
+	}
+
+	// validateStages already ruled out cycles, so every pass starts something
+	for progress := true; progress; {
+		progress = false
+		for i, item := range items {
+			if started[i] {
+				continue
+			}
+
+			ready := true
+			for _, dep := range item.DependsOn {
+				if j, ok := names[dep]; ok && !started[j] {
+					ready = false
+				}
+			}
+			if !ready {
+				continue
+			}
+
+			for _, dep := range item.DependsOn {
+				j, ok := names[dep]
+				if !ok || waited[j] || items[j].ReadyWhen == nil {
+					continue
+				}
+				waited[j] = true
+				timeout, _ := readyTimeout(items[j].ReadyWhen)
+				s.record(planStep{
+					note:  fmt.Sprintf("wait for %s to be ready (gives up after %s)", items[j].Name, timeout),
+					shell: readyWaitShell(paneIDs[j], items[j], timeout),
+				})
+			}
+
+			s.note("start " + item.Name + " (depends on " + strings.Join(item.DependsOn, ", ") + ")")
+			s.startDeferredPane(paneIDs[i], item)
+			started[i] = true
+			progress = true
+		}
+	}
+}
+
+// readyWaitShell polls an item's ready_when like the launcher does,
+// failing the script once timeout has passed
+func readyWaitShell(paneID string, item launchItem, timeout time.Duration) string {
+	attempts := int((timeout + readyPollInterval - 1) / readyPollInterval)
+	giveUp := "echo " + shellQuote(item.Name+" not ready after "+timeout.String()) + " >&2; exit 1"
+	return "tries=0; until " + readyCheckShell(paneID, item) + "; do " +
+		"tries=$((tries + 1)); [ \"$tries\" -le " + strconv.Itoa(attempts) + " ] || { " + giveUp + "; }; " +
+		"sleep " + secondsString(readyPollInterval) + "; done"
+}
+
+// readyCheckShell renders an item's ready_when as a shell condition (see checkReady)
+func readyCheckShell(paneID string, item launchItem) string {
+	rc := item.ReadyWhen
+	var checks []string
+
+	if rc.Port != 0 {
+		host := rc.Host
+		if host == "" {
+			host = "localhost"
+		}
+		checks = append(checks, "nc -z "+shellQuote(host)+" "+strconv.Itoa(rc.Port))
+	}
+	if rc.File != "" {
+		path := expandPath(rc.File)
+		if !filepath.IsAbs(path) && item.Cwd != "" {
+			path = filepath.Join(item.Cwd, path)
+		}
+		checks = append(checks, "test -e "+shellQuote(path))
+	}
+	if rc.Log != "" {
+		// grep -E is close to, but not exactly, Go's regexp syntax
+		checks = append(checks, "tmux capture-pane -p -J -t "+shellQuote(paneID)+" -S -1000 | grep -Eq "+shellQuote(rc.Log))
+	}
+	if rc.Command != "" {
+		check := "sh -c " + shellQuote(rc.Command)
+		if item.Cwd != "" {
+			check = "(cd " + shellQuote(item.Cwd) + " && " + check + ")"
+		}
+		checks = append(checks, check)
+	}
+
+	if len(checks) == 0 {
+		return "true"
+	}
+	return strings.Join(checks, " && ")
+}
+
+// lines renders the plan as shell lines, with comments for notes
+func (p *spawnPlan) lines() []string {
+	var lines []string
+	for _, step := range p.steps {
+		if step.note != "" {
+			lines = append(lines, "# "+step.note)
+		}
+		if step.shell != "" {
+			lines = append(lines, step.shell)
+		}
+		if len(step.args) == 0 {
+			continue
+		}
+
+		cmd := shellJoin(step.args)
+		if step.dir != "" {
+			cmd = "(cd " + shellQuote(step.dir) + " && " + cmd + ")"
+		}
+		if step.background {
+			cmd += " &"
+		}
+
+		switch len(step.assign) {
+		case 0:
+			lines = append(lines, cmd)
+		case 1:
+			lines = append(lines, step.assign[0]+"=$("+cmd+")")
+		default:
+			// Split "W1 P1"-style output into its variables
+			lines = append(lines, "ids=$("+cmd+")")
+			set := "set -- $ids"
+			for i, name := range step.assign {
+				set += "; " + name + "=$" + strconv.Itoa(i+1)
+			}
+			lines = append(lines, set)
+		}
+	}
+
+	if p.err != nil {
+		lines = append(lines, "# a real launch stops here: "+firstLine(p.err.Error()))
+	}
+	return lines
+}
+
+// script renders the plan as a standalone shell script
+func (p *spawnPlan) script() string {
+	var sb strings.Builder
+	sb.WriteString("#!/bin/sh\n")
+	sb.WriteString("# Spawn plan for " + p.title + ", generated by tui-launcher --dry-run\n")
+	sb.WriteString("set -e\n\n")
+	for _, line := range p.lines() {
+		sb.WriteString(line + "\n")
+	}
+	if p.err != nil {
+		sb.WriteString("exit 1\n")
+	}
+	return sb.String()
+}
+
+// writePlanScript saves a plan as an executable script in dir and returns its path
+func writePlanScript(p *spawnPlan, dir string) (string, error) {
+	name := sanitizeSessionName(p.title)
+	if name == "" {
+		name = "launch"
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-plan-%s.sh", name, time.Now().Format("150405")))
+	if err := os.WriteFile(path, []byte(p.script()), 0755); err != nil {
+		return "", fmt.Errorf("failed to write plan: %w", err)
+	}
+	return path, nil
+}
+
+// shellJoin quotes args for sh
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote quotes one argument for sh, leaving placeholder variables expandable
+func shellQuote(arg string) string {
+	if placeholderPattern.MatchString(arg) {
+		return `"` + arg + `"`
+	}
+	if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_@%+=:,./-") == "" {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// secondsString formats a duration as sleep(1) seconds
+func secondsString(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
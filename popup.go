@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// popup.go - tmux popup mode
+// "tui-launcher popup" re-runs the launcher in a display-popup over the
+// current window. The popup has no pane of its own, so the launcher takes
+// over the pane that opened it ($TMUX_PANE): splits, the current window and
+// keys all go there. The popup closes once a launch succeeds
+
+// defaultPopupSize is the popup's width and height without popup: in the config
+const defaultPopupSize = "90%"
+
+// defaultPopupKey is the key (after the prefix) in the printed binding
+const defaultPopupKey = "O"
+
+// runPopup handles "tui-launcher popup [--width W] [--height H] [--print-binding]"
+func runPopup(args []string) error {
+	var popup PopupConfig
+	if loaded := loadConfig().(configLoadedMsg); loaded.err == nil {
+		popup = loaded.config.Popup
+	}
+	if popup.Width == "" {
+		popup.Width = defaultPopupSize
+	}
+	if popup.Height == "" {
+		popup.Height = defaultPopupSize
+	}
+	if popup.Key == "" {
+		popup.Key = defaultPopupKey
+	}
+
+	flags := flag.NewFlagSet("popup", flag.ExitOnError)
+	width := flags.String("width", popup.Width, "popup width in columns or percent of the window")
+	height := flags.String("height", popup.Height, "popup height in lines or percent of the window")
+	printBinding := flags.Bool("print-binding", false, "print a tmux key binding that opens the popup, for ~/.tmux.conf")
+	flags.Parse(args)
+
+	if *printBinding {
+		fmt.Print(popupBinding(popup.Key))
+		return nil
+	}
+	if !insideTmux() {
+		return fmt.Errorf("popup needs tmux: run it in a tmux pane or from a key binding (--print-binding)")
+	}
+
+	// From a pane $TMUX_PANE is set; from run-shell the session's active pane opened the popup
+	origin := os.Getenv("TMUX_PANE")
+	if origin == "" {
+		var err error
+		if origin, err = tmuxOutput("display-message", "-p", "#{pane_id}"); err != nil {
+			return err
+		}
+	}
+	dir, err := tmuxOutput("display-message", "-p", "-t", origin, "#{pane_current_path}")
+	if err != nil {
+		return err
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("tmux", popupArgs(origin, dir, *width, *height, self)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+// popupArgs returns the display-popup command running the launcher (self) for origin
+func popupArgs(origin, dir, width, height, self string) []string {
+	return []string{"display-popup", "-E", "-t", origin, "-d", dir, "-w", width, "-h", height,
+		shellJoin([]string{self, "--popup-pane", origin})}
+}
+
+// popupBinding returns the tmux.conf lines that open the popup with prefix+key
+// run-shell -b keeps tmux responsive while the popup is open
+func popupBinding(key string) string {
+	return "# tui-launcher popup (add to ~/.tmux.conf, then: tmux source-file ~/.tmux.conf)\n" +
+		fmt.Sprintf("bind-key %s run-shell -b \"tui-launcher popup\"\n", key)
+}
+
+// enterPopup makes the launcher act from pane, the pane that opened its popup
+// tmux commands without -t resolve against $TMUX_PANE, so they target it too
+func enterPopup(pane string) error {
+	if pane == "" || pane[0] != '%' {
+		return fmt.Errorf("--popup-pane %q: expected a tmux pane id like %%3", pane)
+	}
+	return os.Setenv("TMUX_PANE", pane)
+}
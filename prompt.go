@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// prompt.go - Boxes shown over the screen
+// The spawn dialog and the text prompts share one bordered box; the prompts
+// also share a single-line text field: Enter submits it, Esc cancels
+
+// promptAction is what a key press did to a prompt
+type promptAction int
+
+const (
+	promptEditing promptAction = iota // The text changed (or the key did nothing)
+	promptSubmit
+	promptCancel
+	promptQuit
+)
+
+// editPrompt applies a key press to a prompt's text field
+func editPrompt(value string, msg tea.KeyMsg) (string, promptAction) {
+	switch msg.String() {
+	case "ctrl+c":
+		return value, promptQuit
+	case "esc":
+		return value, promptCancel
+	case "enter":
+		return value, promptSubmit
+	}
+	return editText(value, msg), promptEditing
+}
+
+// editText applies a key press to a single-line text field
+func editText(value string, msg tea.KeyMsg) string {
+	switch msg.Type {
+	case tea.KeyBackspace:
+		runes := []rune(value)
+		if len(runes) > 0 {
+			return string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		return ""
+	case tea.KeySpace:
+		return value + " "
+	case tea.KeyRunes:
+		return value + string(msg.Runes)
+	}
+	return value
+}
+
+// promptField renders a text field with its cursor
+func promptField(label, value string) string {
+	return label + value + "█"
+}
+
+// viewPromptBox renders lines in a bordered box centered over the screen
+// Lines wider than the screen wrap
+func (m model) viewPromptBox(lines ...string) string {
+	content := strings.Join(lines, "\n")
+	width := lipgloss.Width(content) + 2 // Padding
+	if m.width > 4 && width > m.width-2 {
+		width = m.width - 2
+	}
+
+	box := lipgloss.NewStyle().
+		Border(m.icons.border).
+		BorderForeground(m.theme.activeBorder.GetBorderTopForeground()).
+		Padding(0, 1).
+		Width(width).
+		Render(content)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
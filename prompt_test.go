@@ -0,0 +1,74 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestViewPromptBox(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		lines         []string
+		wantText      string
+	}{
+		{"fits", 60, 10, []string{"New worktree", "", "Branch: main█"}, "Branch: main█"},
+		{"long line wraps on a narrow screen", 24, 12, []string{"Command: claude --model opus 'fix the flaky test'"}, "Command:"},
+		{"before the first window size", 0, 0, []string{"Launch 2 items"}, "Launch 2 items"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := model{width: tt.width, height: tt.height, icons: iconSets[iconsASCII]}
+			got := m.viewPromptBox(tt.lines...)
+			if !strings.Contains(got, tt.wantText) {
+				t.Errorf("box lacks %q:\n%s", tt.wantText, got)
+			}
+			if tt.width == 0 {
+				return
+			}
+			lines := strings.Split(got, "\n")
+			if len(lines) > tt.height {
+				t.Errorf("box is %d lines, screen %d", len(lines), tt.height)
+			}
+			for _, line := range lines {
+				if lipgloss.Width(line) > tt.width {
+					t.Errorf("line %q is wider than %d", line, tt.width)
+				}
+			}
+		})
+	}
+}
+
+func TestEditPrompt(t *testing.T) {
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	tests := []struct {
+		name       string
+		value      string
+		msg        tea.KeyMsg
+		want       string
+		wantAction promptAction
+	}{
+		{"typing", "feat", runes("/x"), "feat/x", promptEditing},
+		{"space", "fix", tea.KeyMsg{Type: tea.KeySpace}, "fix ", promptEditing},
+		{"backspace removes a rune", "café", tea.KeyMsg{Type: tea.KeyBackspace}, "caf", promptEditing},
+		{"backspace on nothing", "", tea.KeyMsg{Type: tea.KeyBackspace}, "", promptEditing},
+		{"ctrl+u clears", "feature", tea.KeyMsg{Type: tea.KeyCtrlU}, "", promptEditing},
+		{"other keys do nothing", "main", tea.KeyMsg{Type: tea.KeyUp}, "main", promptEditing},
+		{"enter submits", "main", tea.KeyMsg{Type: tea.KeyEnter}, "main", promptSubmit},
+		{"esc cancels", "main", tea.KeyMsg{Type: tea.KeyEsc}, "main", promptCancel},
+		{"ctrl+c quits", "main", tea.KeyMsg{Type: tea.KeyCtrlC}, "main", promptQuit},
+		{"q is text", "", runes("q"), "q", promptEditing},
+	}
+
+	for _, tt := range tests {
+		got, action := editPrompt(tt.value, tt.msg)
+		if got != tt.want || action != tt.wantAction {
+			t.Errorf("%s: editPrompt(%q) = %q, %d; want %q, %d", tt.name, tt.value, got, action, tt.want, tt.wantAction)
+		}
+	}
+}
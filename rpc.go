@@ -0,0 +1,492 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// rpc.go - Control socket
+// "tui-launcher serve" runs the launcher without a UI and answers JSON-RPC 2.0
+// requests on a Unix socket, one JSON object per line. Requests reach the
+// model as messages, so launches take the same path as Enter in the TUI:
+// hooks, staging, status tracking and all. "tui-launcher client" is a thin
+// command-line client for scripts and editor plugins
+//
+// Methods:
+//   list                         the global and project trees
+//   launch {path, args, prompt}  launch a command or profile by path (or name)
+//   reload                       read the config again
+//   status {path}                tracked processes, of one item or all
+
+// JSON-RPC error codes
+const (
+	rpcParseError     = -32700
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcServerError    = -32000 // Launch or config failure
+)
+
+// rpcRequest is a JSON-RPC request read from the socket
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcResponse is the answer to one request
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a failed request
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// rpcMsg carries a request into Update; the answer goes back on reply
+type rpcMsg struct {
+	req   rpcRequest
+	reply chan<- rpcResponse
+}
+
+// rpcPending is a launch request waiting for its spawn to complete
+type rpcPending struct {
+	id    json.RawMessage
+	reply chan<- rpcResponse
+}
+
+// rpcItem is a tree item as listed by the list method
+type rpcItem struct {
+	Path     string    `json:"path"`
+	Name     string    `json:"name"`
+	Type     string    `json:"type"`
+	Command  string    `json:"command,omitempty"`
+	Status   string    `json:"status,omitempty"`
+	Children []rpcItem `json:"children,omitempty"`
+}
+
+// rpcLaunchParams are the parameters of launch
+type rpcLaunchParams struct {
+	Path   string   `json:"path"`
+	Args   []string `json:"args"`   // Appended (quoted) to a command
+	Prompt string   `json:"prompt"` // Prompt for an AI tool (instead of its prompt:)
+}
+
+// rpcLaunchResult is what a launch created
+type rpcLaunchResult struct {
+	Path    string   `json:"path"`
+	Panes   []string `json:"panes,omitempty"`   // tmux pane ids
+	PID     int      `json:"pid,omitempty"`     // xterm process
+	Session string   `json:"session,omitempty"` // Detached session to attach to (outside tmux)
+	Warning string   `json:"warning,omitempty"` // Failed after hook
+}
+
+// rpcStatus is the state of a launched item
+type rpcStatus struct {
+	Path     string          `json:"path"`
+	State    string          `json:"state"`
+	ExitCode int             `json:"exit_code"` // -1 when unknown
+	Panes    []rpcPaneStatus `json:"panes"`
+}
+
+// rpcPaneStatus is the state of one pane or process of an item
+type rpcPaneStatus struct {
+	Pane     string `json:"pane,omitempty"`
+	PID      int    `json:"pid,omitempty"`
+	State    string `json:"state"`
+	ExitCode int    `json:"exit_code"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// defaultSocketPath is where serve listens and client connects without --socket
+func defaultSocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "tui-launcher.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("tui-launcher-%d.sock", os.Getuid()))
+}
+
+// runServe handles "tui-launcher serve [--socket PATH]"
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	socket := flags.String("socket", defaultSocketPath(), "Unix socket to listen on")
+	flags.Parse(args)
+
+	// A socket nobody answers on is left over from a server that died
+	if conn, err := net.Dial("unix", *socket); err == nil {
+		conn.Close()
+		return fmt.Errorf("a server is already listening on %s", *socket)
+	}
+	os.Remove(*socket)
+
+	listener, err := listenPrivate(*socket)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(*socket)
+	defer listener.Close()
+
+	m := initialModel()
+	m.rpcLaunches = make(map[int]rpcPending)
+	m.serving = true
+	p := tea.NewProgram(m, tea.WithoutRenderer(), tea.WithInput(nil))
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveConn(conn, p.Send, done)
+		}
+	}()
+
+	fmt.Fprintf(os.Stderr, "tui-launcher: listening on %s\n", *socket)
+	_, err = p.Run()
+	close(done)
+	return err
+}
+
+// listenPrivate listens on a Unix socket at path that only this user can connect to
+// The socket is bound in a fresh 0700 directory, restricted to 0600 and then moved
+// into place, so it is never reachable with looser permissions
+func listenPrivate(path string) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".tui-launcher-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "sock")
+	listener, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	listener.(*net.UnixListener).SetUnlinkOnClose(false) // It won't be at tmp any more
+	if err = os.Chmod(tmp, 0600); err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// serveConn answers the requests of one connection in order
+// Requests reach the model through send; notifications (no id) get no answer
+func serveConn(conn net.Conn, send func(tea.Msg), done <-chan struct{}) {
+	defer conn.Close()
+	encoder := json.NewEncoder(conn)
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		var req rpcRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			encoder.Encode(rpcFailure(nil, rpcParseError, err.Error()))
+			continue
+		}
+
+		reply := make(chan rpcResponse, 1) // Buffered: an unread answer doesn't block Update
+		send(rpcMsg{req: req, reply: reply})
+		if len(req.ID) == 0 {
+			continue
+		}
+		select {
+		case resp := <-reply:
+			if err := encoder.Encode(resp); err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// rpcResult is a successful response
+func rpcResult(id json.RawMessage, result any) rpcResponse {
+	return rpcResponse{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+// rpcFailure is an error response
+func rpcFailure(id json.RawMessage, code int, message string) rpcResponse {
+	return rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
+
+// handleRPC answers a request
+// Launches answer once their spawn completes (see finishRPCLaunch)
+func (m model) handleRPC(msg rpcMsg) (tea.Model, tea.Cmd) {
+	req := msg.req
+	switch req.Method {
+	case "list":
+		msg.reply <- rpcResult(req.ID, map[string][]rpcItem{
+			"global":   m.rpcItems(m.globalItems),
+			"projects": m.rpcItems(m.projectItems),
+		})
+
+	case "launch":
+		var params rpcLaunchParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.Path == "" {
+			msg.reply <- rpcFailure(req.ID, rpcInvalidParams, "launch needs {\"path\": ...}")
+			return m, nil
+		}
+		// The launch takes the next id; a launch that doesn't (a dry run
+		// only plans) must be answered now, or nothing ever answers it
+		id := m.launchSeq + 1
+		cmd, err := m.launchPath(params)
+		if err != nil {
+			msg.reply <- rpcFailure(req.ID, rpcInvalidParams, err.Error())
+			return m, nil
+		}
+		if m.launchSeq != id {
+			msg.reply <- rpcFailure(req.ID, rpcServerError, "nothing was launched (dry run)")
+			return m, cmd
+		}
+		m.rpcLaunches[id] = rpcPending{id: req.ID, reply: msg.reply}
+		return m, cmd
+
+	case "reload":
+		loaded := loadConfig().(configLoadedMsg)
+		if loaded.err != nil {
+			msg.reply <- rpcFailure(req.ID, rpcServerError, loaded.err.Error())
+			return m, nil
+		}
+		next, cmd := m.update(loaded)
+		m = next.(model)
+		msg.reply <- rpcResult(req.ID, map[string]int{"items": len(m.rpcPaths())})
+		return m, cmd
+
+	case "status":
+		var params struct {
+			Path string `json:"path"`
+		}
+		if len(req.Params) > 0 {
+			if err := json.Unmarshal(req.Params, &params); err != nil {
+				msg.reply <- rpcFailure(req.ID, rpcInvalidParams, err.Error())
+				return m, nil
+			}
+		}
+		msg.reply <- rpcResult(req.ID, m.rpcStatuses(params.Path))
+
+	default:
+		msg.reply <- rpcFailure(req.ID, rpcMethodNotFound, "unknown method "+req.Method)
+	}
+	return m, nil
+}
+
+// launchPath starts the launch of an item the way Enter would
+// Commands get params' args and prompt; profiles take neither
+func (m *model) launchPath(params rpcLaunchParams) (tea.Cmd, error) {
+	item, ok := findItem(append(append([]launchItem{}, m.globalItems...), m.projectItems...), params.Path)
+	if !ok {
+		return nil, fmt.Errorf("no item %q", params.Path)
+	}
+
+	switch item.ItemType {
+	case typeCommand:
+		prompt := item.Prompt
+		if params.Prompt != "" {
+			prompt = params.Prompt
+		}
+		item = m.contextItem(item, prompt)
+		if len(params.Args) > 0 {
+			item.Command += " " + shellJoin(params.Args)
+		}
+		return m.startLaunch(item.Name, []launchItem{item}, []launchItem{item}, func(s *spawner) spawnCompleteMsg {
+			return s.single(item, item.DefaultSpawn)
+		}), nil
+
+	case typeProfile:
+		if len(params.Args) > 0 || params.Prompt != "" {
+			return nil, fmt.Errorf("%s is a profile; args and prompt only apply to commands", item.Path)
+		}
+		return m.launchProfile(item), nil
+
+	default:
+		return nil, fmt.Errorf("%s is a %s; only commands and profiles can be launched", item.Path, item.ItemType)
+	}
+}
+
+// finishRPCLaunch answers the launch request a spawn result belongs to, if any
+func (m *model) finishRPCLaunch(msg spawnCompleteMsg) {
+	pending, ok := m.rpcLaunches[msg.launchID]
+	if !ok {
+		return
+	}
+	delete(m.rpcLaunches, msg.launchID)
+
+	if msg.err != nil {
+		pending.reply <- rpcFailure(pending.id, rpcServerError, msg.err.Error())
+		return
+	}
+	result := rpcLaunchResult{Path: msg.procItem.Path, Panes: msg.paneIDs, Session: msg.attach}
+	if len(msg.paneItems) > 0 {
+		result.Path = msg.paneItems[0].Path
+	}
+	if msg.proc != nil {
+		result.PID = msg.proc.Process.Pid
+	}
+	if msg.hookErr != nil {
+		result.Warning = msg.hookErr.Error()
+	}
+	pending.reply <- rpcResult(pending.id, result)
+}
+
+// rpcItems converts tree items for list, with the status of launched ones
+func (m model) rpcItems(items []launchItem) []rpcItem {
+	result := []rpcItem{}
+	for _, item := range items {
+		entry := rpcItem{
+			Path:     item.Path,
+			Name:     item.Name,
+			Type:     strings.ToLower(item.ItemType.String()),
+			Command:  item.Command,
+			Children: m.rpcItems(item.Children),
+		}
+		if tracked := m.tracked[item.Path]; len(tracked) > 0 {
+			state, _ := aggregateState(tracked)
+			entry.Status = state.String()
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// rpcPaths lists the paths of every item in both trees
+func (m model) rpcPaths() []string {
+	var paths []string
+	var walk func(items []launchItem)
+	walk = func(items []launchItem) {
+		for _, item := range items {
+			paths = append(paths, item.Path)
+			walk(item.Children)
+		}
+	}
+	walk(m.globalItems)
+	walk(m.projectItems)
+	return paths
+}
+
+// rpcStatuses reports tracked processes, of one item ("" = every launched item)
+func (m model) rpcStatuses(path string) []rpcStatus {
+	statuses := []rpcStatus{}
+	for _, itemPath := range m.rpcPaths() {
+		tracked := m.tracked[itemPath]
+		if len(tracked) == 0 || (path != "" && itemPath != path) {
+			continue
+		}
+		state, exitCode := aggregateState(tracked)
+		status := rpcStatus{Path: itemPath, State: state.String(), ExitCode: exitCode}
+		for _, tp := range tracked {
+			pane := rpcPaneStatus{Pane: tp.paneID, State: tp.state.String(), ExitCode: tp.exitCode, Detail: tp.detail}
+			if tp.proc != nil {
+				pane.PID = tp.proc.Pid
+			}
+			status.Panes = append(status.Panes, pane)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// runClient handles "tui-launcher client [--socket PATH] <method> [args]"
+//
+//	list | launch <path> [args...] | reload | status [path]
+func runClient(args []string) error {
+	flags := flag.NewFlagSet("client", flag.ExitOnError)
+	socket := flags.String("socket", defaultSocketPath(), "Unix socket of the server")
+	prompt := flags.String("prompt", "", "with launch, the prompt for an AI tool")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: tui-launcher client [--socket PATH] list | launch [--prompt TEXT] <path> [args...] | reload | status [path]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("client: missing method")
+	}
+
+	method, rest := flags.Arg(0), flags.Args()[1:]
+	var params any
+	switch method {
+	case "list", "reload":
+	case "launch":
+		// Flags may also follow the method: client launch --prompt "..." ai/Claude
+		flags.Parse(rest)
+		rest = flags.Args()
+		if len(rest) == 0 {
+			return errors.New("client: launch needs an item path")
+		}
+		params = rpcLaunchParams{Path: rest[0], Args: rest[1:], Prompt: *prompt}
+	case "status":
+		if len(rest) > 0 {
+			params = map[string]string{"path": rest[0]}
+		}
+	default:
+		return fmt.Errorf("client: unknown method %q", method)
+	}
+
+	result, err := callRPC(*socket, method, params)
+	if err != nil {
+		return err
+	}
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, result, "", "  "); err != nil {
+		return err
+	}
+	fmt.Println(pretty.String())
+	return nil
+}
+
+// callRPC sends one request to the server and returns its result
+func callRPC(socket, method string, params any) (json.RawMessage, error) {
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		if errors.Is(err, syscall.ENOENT) || errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, fmt.Errorf("no server on %s (start one with: tui-launcher serve)", socket)
+		}
+		return nil, err
+	}
+	defer conn.Close()
+
+	req := map[string]any{"jsonrpc": "2.0", "id": 1, "method": method}
+	if params != nil {
+		req["params"] = params
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.Error != nil {
+		return nil, errors.New(resp.Error.Message)
+	}
+	if len(resp.Result) == 0 {
+		return json.RawMessage("null"), nil
+	}
+	return resp.Result, nil
+}
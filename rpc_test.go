@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// rpcTestModel is a model with a small tree and no config
+func rpcTestModel() model {
+	return model{
+		globalItems: []launchItem{
+			{Name: "Tools", Path: "tools", ItemType: typeCategory, Children: []launchItem{
+				{Name: "htop", Path: "tools/htop", ItemType: typeCommand, Command: "htop"},
+			}},
+		},
+		projectItems: []launchItem{
+			{Name: "app", Path: "projects/app", ItemType: typeCategory, Children: []launchItem{
+				{Name: "dev", Path: "projects/app/dev", ItemType: typeProfile, Children: []launchItem{
+					{Name: "server", Path: "projects/app/dev/server", ItemType: typeCommand, Command: "make run"},
+				}},
+			}},
+		},
+		rpcLaunches: make(map[int]rpcPending),
+	}
+}
+
+// sendRPC sends one request to m and returns the updated model and the request's reply channel
+func sendRPC(m model, method, params string) (model, chan rpcResponse) {
+	req := rpcRequest{JSONRPC: "2.0", ID: json.RawMessage("7"), Method: method}
+	if params != "" {
+		req.Params = json.RawMessage(params)
+	}
+	reply := make(chan rpcResponse, 1)
+	next, _ := m.handleRPC(rpcMsg{req: req, reply: reply})
+	return next.(model), reply
+}
+
+// answer returns the reply sent on reply, if any
+func answer(reply chan rpcResponse) *rpcResponse {
+	select {
+	case resp := <-reply:
+		return &resp
+	default:
+		return nil
+	}
+}
+
+func TestHandleRPC(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		params      string
+		wantCode    int    // 0 = success
+		wantMessage string // Part of the error message
+		wantResult  string // JSON of the result, "" = don't check
+		wantPending bool   // No answer until the spawn completes
+	}{
+		{"unknown method", "explode", "", rpcMethodNotFound, "unknown method explode", "", false},
+		{"list", "list", "", 0, "", `{"global":[{"path":"tools","name":"Tools","type":"category","children":[{"path":"tools/htop","name":"htop","type":"command","command":"htop"}]}],` +
+			`"projects":[{"path":"projects/app","name":"app","type":"category","children":[{"path":"projects/app/dev","name":"dev","type":"profile","children":[{"path":"projects/app/dev/server","name":"server","type":"command","command":"make run"}]}]}]}`, false},
+		{"status of nothing launched", "status", "", 0, "", `[]`, false},
+		{"status with bad params", "status", `[1]`, rpcInvalidParams, "cannot unmarshal", "", false},
+		{"launch without params", "launch", "", rpcInvalidParams, `launch needs {"path": ...}`, "", false},
+		{"launch without a path", "launch", `{"args": ["x"]}`, rpcInvalidParams, "launch needs", "", false},
+		{"launch unknown item", "launch", `{"path": "tools/nope"}`, rpcInvalidParams, `no item "tools/nope"`, "", false},
+		{"launch a category", "launch", `{"path": "tools"}`, rpcInvalidParams, "only commands and profiles can be launched", "", false},
+		{"profile with args", "launch", `{"path": "projects/app/dev", "args": ["-v"]}`, rpcInvalidParams, "args and prompt only apply to commands", "", false},
+		{"launch a command by name", "launch", `{"path": "htop", "args": ["-d", "10"]}`, 0, "", "", true},
+		{"launch a profile", "launch", `{"path": "projects/app/dev"}`, 0, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, reply := sendRPC(rpcTestModel(), tt.method, tt.params)
+			resp := answer(reply)
+
+			if tt.wantPending {
+				if resp != nil {
+					t.Fatalf("answered before the spawn completed: %+v", resp)
+				}
+				if _, ok := m.rpcLaunches[m.launchSeq]; !ok {
+					t.Fatalf("launch %d not waiting for its spawn", m.launchSeq)
+				}
+				return
+			}
+			if resp == nil {
+				t.Fatal("no answer")
+			}
+			if string(resp.ID) != "7" || resp.JSONRPC != "2.0" {
+				t.Errorf("answer has id %s, jsonrpc %q", resp.ID, resp.JSONRPC)
+			}
+			if tt.wantCode != 0 {
+				if resp.Error == nil || resp.Error.Code != tt.wantCode || !strings.Contains(resp.Error.Message, tt.wantMessage) {
+					t.Fatalf("error = %+v, want code %d containing %q", resp.Error, tt.wantCode, tt.wantMessage)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %+v", resp.Error)
+			}
+			if tt.wantResult != "" {
+				got, _ := json.Marshal(resp.Result)
+				if string(got) != tt.wantResult {
+					t.Errorf("result = %s\nwant     %s", got, tt.wantResult)
+				}
+			}
+		})
+	}
+}
+
+func TestFinishRPCLaunch(t *testing.T) {
+	server := launchItem{Name: "server", Path: "projects/app/dev/server"}
+
+	tests := []struct {
+		name       string
+		msg        spawnCompleteMsg
+		wantResult *rpcLaunchResult
+		wantError  string
+	}{
+		{
+			"panes created",
+			spawnCompleteMsg{paneIDs: []string{"%3", "%4"}, paneItems: []launchItem{server, server}},
+			&rpcLaunchResult{Path: "projects/app/dev/server", Panes: []string{"%3", "%4"}},
+			"",
+		},
+		{
+			"after hook failed",
+			spawnCompleteMsg{paneIDs: []string{"%3"}, paneItems: []launchItem{server}, hookErr: errors.New(`after hook "notify" failed: exit status 1`)},
+			&rpcLaunchResult{Path: "projects/app/dev/server", Panes: []string{"%3"}, Warning: `after hook "notify" failed: exit status 1`},
+			"",
+		},
+		{"spawn failed", spawnCompleteMsg{err: errors.New("no server running")}, nil, "no server running"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, reply := sendRPC(rpcTestModel(), "launch", `{"path": "projects/app/dev"}`)
+			id := m.launchSeq
+
+			// Results of other launches don't answer it
+			other := tt.msg
+			other.launchID = id + 1
+			m.finishRPCLaunch(other)
+			if resp := answer(reply); resp != nil {
+				t.Fatalf("answered by another launch's result: %+v", resp)
+			}
+
+			tt.msg.launchID = id
+			m.finishRPCLaunch(tt.msg)
+			if _, ok := m.rpcLaunches[id]; ok {
+				t.Fatal("still pending after its spawn completed")
+			}
+
+			got := answer(reply)
+			if got == nil {
+				t.Fatal("no answer")
+			}
+			if tt.wantError != "" {
+				if got.Error == nil || !strings.Contains(got.Error.Message, tt.wantError) {
+					t.Fatalf("error = %+v, want %q", got.Error, tt.wantError)
+				}
+				return
+			}
+			if got.Error != nil || !reflect.DeepEqual(got.Result, *tt.wantResult) {
+				t.Fatalf("answer = %+v, want result %+v", got, *tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestHandleRPCDryRunLaunch(t *testing.T) {
+	m := rpcTestModel()
+	m.dryRun = true
+	m, reply := sendRPC(m, "launch", `{"path": "tools/htop"}`)
+
+	resp := answer(reply)
+	if resp == nil || resp.Error == nil || resp.Error.Code != rpcServerError || !strings.Contains(resp.Error.Message, "dry run") {
+		t.Fatalf("answer = %+v, want a dry run error right away", resp)
+	}
+	if len(m.rpcLaunches) > 0 {
+		t.Fatalf("dry run left launches waiting: %v", m.rpcLaunches)
+	}
+}
+
+func TestServeConnNotifications(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	m := rpcTestModel()
+	var methods []string
+	send := func(msg tea.Msg) {
+		req := msg.(rpcMsg)
+		methods = append(methods, req.req.Method)
+		next, _ := m.handleRPC(req)
+		m = next.(model)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go serveConn(server, send, done)
+
+	// Notifications, even failing ones, are handled but never answered
+	requests := []string{
+		`{"jsonrpc": "2.0", "method": "status"}`,
+		`{"jsonrpc": "2.0", "id": 1, "method": "status"}`,
+		`{"jsonrpc": "2.0", "method": "explode"}`,
+		`{"jsonrpc": "2.0", "method": "launch", "params": {"path": "htop"}}`,
+		`{"jsonrpc": "2.0", "id": "last", "method": "explode"}`,
+	}
+	go client.Write([]byte(strings.Join(requests, "\n") + "\n"))
+
+	decoder := json.NewDecoder(client)
+	for _, wantID := range []string{`1`, `"last"`} {
+		var resp rpcResponse
+		if err := decoder.Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if string(resp.ID) != wantID {
+			t.Fatalf("answer to id %s, want %s", resp.ID, wantID)
+		}
+	}
+	if want := []string{"status", "status", "explode", "launch", "explode"}; !reflect.DeepEqual(methods, want) {
+		t.Errorf("handled %q, want %q", methods, want)
+	}
+}
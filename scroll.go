@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// scroll.go - Tree viewports
+// Each tree pane keeps the first visible row in globalOffset/projectOffset.
+// Update moves the offset just enough to keep the cursor in view, so the
+// view only ever reads it
+
+// treeRows is the number of items a tree pane shows (its height minus title and blank line)
+func (m model) treeRows() int {
+	_, _, treeHeight, _ := m.calculateLayout()
+	return max(treeHeight-2, 1)
+}
+
+// scrollToCursor returns the offset closest to offset that shows cursor
+func scrollToCursor(offset, cursor, total, rows int) int {
+	if cursor < offset {
+		offset = cursor
+	}
+	if cursor >= offset+rows {
+		offset = cursor - rows + 1
+	}
+	return clampOffset(offset, total, rows)
+}
+
+// followCursors scrolls both trees so their cursors stay visible
+func (m *model) followCursors() {
+	rows := m.treeRows()
+	m.globalOffset = scrollToCursor(m.globalOffset, m.globalCursor, len(m.globalTreeItems), rows)
+	m.projectOffset = scrollToCursor(m.projectOffset, m.projectCursor, len(m.projectTreeItems), rows)
+}
+
+// projectsFocused reports whether keys act on the projects tree
+func (m model) projectsFocused() bool {
+	if m.getLayoutMode() == layoutDesktop {
+		return m.activePane == paneProject
+	}
+	return m.showingProjects
+}
+
+// moveCursor moves the focused tree's cursor by delta, stopping at the ends
+func (m *model) moveCursor(delta int) {
+	cursor, total := &m.globalCursor, len(m.globalTreeItems)
+	if m.projectsFocused() {
+		cursor, total = &m.projectCursor, len(m.projectTreeItems)
+	}
+	*cursor = max(min(*cursor+delta, total-1), 0)
+}
+
+// treeViewport renders the visible rows of a tree, with a scrollbar when it doesn't fit
+func (m model) treeViewport(items []launchTreeItem, cursor, offset int, focused bool, expanded map[string]bool, width, rows int) []string {
+	offset = scrollToCursor(offset, cursor, len(items), rows)
+	end := min(offset+rows, len(items))
+
+	var lines []string
+	for i := offset; i < end; i++ {
+		lines = append(lines, m.treeLine(items[i], i, cursor, focused, expanded[items[i].item.Path], width))
+	}
+	if len(items) <= rows {
+		return lines
+	}
+
+	// The thumb covers the visible share of the tree, at least one row
+	thumb := max(rows*rows/len(items), 1)
+	thumbStart := offset * (rows - thumb) / max(len(items)-rows, 1)
+	for len(lines) < rows {
+		lines = append(lines, "")
+	}
+	for i, line := range lines {
+		bar := m.theme.muted.Render(m.icons.scrollTrack)
+		if i >= thumbStart && i < thumbStart+thumb {
+			bar = m.theme.scrollbar.Render(m.icons.scrollThumb)
+		}
+		pad := max(width-1-lipgloss.Width(line), 0)
+		lines[i] = line + strings.Repeat(" ", pad) + bar
+	}
+	return lines
+}
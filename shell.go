@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// shell.go - cd on exit
+// Enter on a project quits and leaves its directory for the shell to cd into.
+// With --cd-fd the directory goes to a file descriptor the shell-init wrapper
+// captures; without it, to ~/.tui-launcher_cd_target (shared by all launchers)
+
+// cdTarget receives the directory to cd into when set by --cd-fd
+var cdTarget *os.File
+
+// setCDFD sends the cd target to file descriptor fd instead of the file in $HOME
+func setCDFD(fd int) error {
+	f := os.NewFile(uintptr(fd), fmt.Sprintf("fd %d", fd))
+	if f == nil {
+		return fmt.Errorf("--cd-fd %d: invalid file descriptor", fd)
+	}
+	if _, err := f.Stat(); err != nil {
+		return fmt.Errorf("--cd-fd %d: file descriptor is not open", fd)
+	}
+	cdTarget = f
+	return nil
+}
+
+// writeCDTarget leaves path for the shell wrapper to cd into
+func writeCDTarget(path string) error {
+	if cdTarget != nil {
+		_, err := fmt.Fprintln(cdTarget, path)
+		return err
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	targetFile := filepath.Join(homeDir, ".tui-launcher_cd_target")
+	return os.WriteFile(targetFile, []byte(path), 0644)
+}
+
+// shellWrapper is a shell's wrapper function and how it is installed
+type shellWrapper struct {
+	install string // Line for the shell's startup file
+	script  string
+}
+
+// shellWrappers are the wrappers printed by shell-init
+// The UI draws on the terminal while fd 3 carries the directory out of the
+// command substitution, so concurrent launchers never share a file.
+// Subcommands skip all that: their output must reach stdout
+var shellWrappers = map[string]shellWrapper{
+	"bash": {`~/.bashrc: eval "$(tui-launcher shell-init bash)"`, posixWrapper},
+	"zsh":  {`~/.zshrc: eval "$(tui-launcher shell-init zsh)"`, posixWrapper},
+	"fish": {`~/.config/fish/config.fish: tui-launcher shell-init fish | source`, `function tl
+    # Subcommands (popup, serve, client, ...) run as they are
+    if set -q argv[1]; and not string match -q -- '-*' $argv[1]
+        command tui-launcher $argv
+        return
+    end
+    set -l dir (command tui-launcher --cd-fd 3 $argv 3>&1 1>/dev/tty)
+    set -l code $status
+    if test -n "$dir"; and test -d "$dir"
+        cd $dir
+    end
+    return $code
+end
+`},
+}
+
+// posixWrapper is the wrapper for bash and zsh
+const posixWrapper = `tl() {
+    # Subcommands (popup, serve, client, ...) run as they are
+    case "$1" in
+        "" | -*) ;;
+        *) command tui-launcher "$@"; return ;;
+    esac
+    local dir code
+    dir="$(command tui-launcher --cd-fd 3 "$@" 3>&1 1>/dev/tty)"
+    code=$?
+    if [ -n "$dir" ] && [ -d "$dir" ]; then
+        cd -- "$dir" || return
+    fi
+    return $code
+}
+`
+
+// shellInit returns the wrapper function for shell
+func shellInit(shell string) (string, error) {
+	wrapper, ok := shellWrappers[shell]
+	if !ok {
+		return "", fmt.Errorf("shell-init: unsupported shell %q (use bash, zsh or fish)", shell)
+	}
+	return "# tui-launcher: tl runs the launcher and cds into the project picked with Enter\n" +
+		"# Add to " + wrapper.install + "\n" + wrapper.script, nil
+}
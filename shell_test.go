@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShellInit(t *testing.T) {
+	tests := []struct {
+		shell       string
+		wantInstall string // "" = unsupported
+		wantScript  string
+	}{
+		{"bash", "~/.bashrc", "tl() {"},
+		{"zsh", "~/.zshrc", "tl() {"},
+		{"fish", "~/.config/fish/config.fish", "function tl"},
+		{"", "", ""},
+		{"tcsh", "", ""},
+	}
+
+	for _, tt := range tests {
+		script, err := shellInit(tt.shell)
+		if tt.wantInstall == "" {
+			if err == nil || !strings.Contains(err.Error(), "unsupported shell") {
+				t.Errorf("shellInit(%q) error = %v, want unsupported shell", tt.shell, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("shellInit(%q): %v", tt.shell, err)
+			continue
+		}
+		for _, want := range []string{"# Add to " + tt.wantInstall, tt.wantScript, "--cd-fd 3"} {
+			if !strings.Contains(script, want) {
+				t.Errorf("shellInit(%q) lacks %q:\n%s", tt.shell, want, script)
+			}
+		}
+	}
+}
+
+func TestShellWrappersParse(t *testing.T) {
+	checks := map[string][]string{
+		"bash": {"bash", "-n"},
+		"zsh":  {"zsh", "-n"},
+		"fish": {"fish", "--no-execute"},
+	}
+	for shell, check := range checks {
+		t.Run(shell, func(t *testing.T) {
+			if _, err := exec.LookPath(check[0]); err != nil {
+				t.Skipf("%s not installed", check[0])
+			}
+			script, err := shellInit(shell)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cmd := exec.Command(check[0], check[1:]...)
+			cmd.Stdin = strings.NewReader(script)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("%s rejects the wrapper: %v\n%s", shell, err, out)
+			}
+		})
+	}
+}
+
+func TestWriteCDTarget(t *testing.T) {
+	t.Cleanup(func() { cdTarget = nil })
+
+	t.Run("home file without --cd-fd", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		cdTarget = nil
+		if err := writeCDTarget("/src/app"); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(filepath.Join(home, ".tui-launcher_cd_target"))
+		if err != nil || string(data) != "/src/app" {
+			t.Fatalf("cd target file = %q, %v; want /src/app", data, err)
+		}
+	})
+
+	t.Run("file descriptor with --cd-fd", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		defer w.Close()
+		if err := setCDFD(int(w.Fd())); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeCDTarget("/src/my app"); err != nil {
+			t.Fatal(err)
+		}
+		line, err := bufio.NewReader(r).ReadString('\n')
+		if err != nil || line != "/src/my app\n" {
+			t.Fatalf("read %q, %v; want the path and a newline", line, err)
+		}
+	})
+
+	t.Run("closed file descriptor", func(t *testing.T) {
+		if err := setCDFD(987); err == nil || !strings.Contains(err.Error(), "not open") {
+			t.Fatalf("setCDFD(987) error = %v, want not open", err)
+		}
+	})
+}
+
+func TestShellWrapperSubcommands(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not installed")
+	}
+	// A fake launcher that prints how it was called
+	bin := t.TempDir()
+	fake := "#!/bin/sh\necho \"called: $*\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "tui-launcher"), []byte(fake), 0755); err != nil {
+		t.Fatal(err)
+	}
+	script, err := shellInit("bash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"popup --width 80", "called: popup --width 80"},
+		{"shell-init zsh", "called: shell-init zsh"},
+		{"client status 'my app'", "called: client status my app"},
+	}
+	for _, tt := range tests {
+		cmd := exec.Command("bash", "-c", script+"\ntl "+tt.args)
+		cmd.Env = append(os.Environ(), "PATH="+bin+":"+os.Getenv("PATH"))
+		out, err := cmd.CombinedOutput()
+		if err != nil || strings.TrimSpace(string(out)) != tt.want {
+			t.Errorf("tl %s = %q, %v; want %q", tt.args, out, err, tt.want)
+		}
+	}
+}
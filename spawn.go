@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,241 +13,356 @@ import (
 
 // spawn.go - Tmux/Xterm spawn logic
 // Based on tmuxplexer's proven implementation
+// Every invocation goes through a spawner, so a dry run can record the
+// exact same sequence instead of executing it (see plan.go)
+
+// liveSpawner executes for real; it keeps no state, so it is safe to share
+var liveSpawner = &spawner{}
 
 // insideTmux checks if we're currently inside a tmux session
 func insideTmux() bool {
 	return os.Getenv("TMUX") != ""
 }
 
-// spawnSingle spawns a single command
-func spawnSingle(item launchItem, mode spawnMode) tea.Cmd {
-	return func() tea.Msg {
-		var err error
-
-		switch mode {
-		case spawnTmuxSplitH:
-			err = tmuxSplitHorizontal(item)
-		case spawnTmuxSplitV:
-			err = tmuxSplitVertical(item)
-		case spawnTmuxWindow:
-			err = tmuxNewWindow(item)
-		case spawnXtermWindow:
-			err = xtermWindow(item)
-		case spawnCurrentPane:
-			err = tmuxCurrentPane(item)
-		default:
-			// Auto-detect: use tmux if inside tmux, otherwise xterm
-			if insideTmux() {
-				err = tmuxSplitHorizontal(item)
-			} else {
-				err = xtermWindow(item)
-			}
+// single spawns one command with the given spawn mode
+func (s *spawner) single(item launchItem, mode spawnMode) spawnCompleteMsg {
+	var paneID string
+	var proc *exec.Cmd
+	var err error
+
+	switch mode {
+	case spawnTmuxSplitH:
+		paneID, err = s.tmuxSplitHorizontal(item)
+	case spawnTmuxSplitV:
+		paneID, err = s.tmuxSplitVertical(item)
+	case spawnTmuxWindow:
+		paneID, err = s.tmuxNewWindow(item)
+	case spawnXtermWindow:
+		proc, err = s.xtermWindow(item)
+	case spawnCurrentPane:
+		paneID, err = s.tmuxCurrentPane(item)
+	case spawnBesideEditor:
+		if insideTmux() {
+			paneID, err = s.tmuxBesideEditor(item)
+		} else {
+			proc, err = s.xtermWindow(item)
+		}
+	default:
+		// Auto-detect: use tmux if inside tmux, otherwise xterm
+		if insideTmux() {
+			paneID, err = s.tmuxSplitHorizontal(item)
+		} else {
+			proc, err = s.xtermWindow(item)
 		}
+	}
 
-		return spawnCompleteMsg{err: err}
+	msg := spawnCompleteMsg{err: err, proc: proc, procItem: item}
+	if paneID != "" {
+		// Splits and windows run the command as the pane process;
+		// current-pane types it, which is what restart has to repeat
+		tracked := item
+		tracked.RunAs = runProcess
+		if mode == spawnCurrentPane {
+			tracked.RunAs = runKeys
+		}
+		msg.paneIDs = []string{paneID}
+		msg.paneItems = []launchItem{tracked}
 	}
+	return msg
 }
 
-// spawnMultiple spawns multiple commands with a layout
+// multiple spawns several commands with a layout
 // Uses the tmuxplexer strategy: create all panes, then apply layout
-func spawnMultiple(items []launchItem, layout tmuxLayout) tea.Cmd {
-	return func() tea.Msg {
-		if len(items) == 0 {
-			return spawnCompleteMsg{err: fmt.Errorf("no items to spawn")}
-		}
+func (s *spawner) multiple(items []launchItem, layout tmuxLayout, opts batchOptions) spawnCompleteMsg {
+	if len(items) == 0 {
+		return spawnCompleteMsg{err: fmt.Errorf("no items to spawn")}
+	}
 
-		// Get common working directory (use first item's)
-		baseDir := items[0].Cwd
-		if baseDir == "" {
-			baseDir = os.Getenv("HOME")
-		}
+	// Get common working directory (use first item's)
+	baseDir := items[0].Cwd
+	if baseDir == "" {
+		baseDir = os.Getenv("HOME")
+	}
 
-		var err error
+	target := opts.target
+	if target == targetAuto {
+		// Legacy behavior: current window inside tmux, new session outside
 		if insideTmux() {
-			// Inside tmux: spawn in current session
-			err = spawnInCurrentSession(items, layout, baseDir)
+			target = targetCurrentWindow
 		} else {
-			// Outside tmux: create new session
-			err = spawnNewSession(items, layout, baseDir)
+			target = targetSwitchSession
 		}
+	}
+	if (target == targetCurrentWindow || target == targetNewWindow) && !insideTmux() {
+		return spawnCompleteMsg{err: fmt.Errorf("spawn target %s requires running inside tmux", target)}
+	}
 
+	// Validate depends_on/ready_when before touching tmux
+	if err := validateStages(items); err != nil {
 		return spawnCompleteMsg{err: err}
 	}
-}
 
-// spawnInCurrentSession spawns items in the current tmux session
-func spawnInCurrentSession(items []launchItem, layout tmuxLayout, baseDir string) error {
-	// Get current session name
-	cmd := exec.Command("tmux", "display-message", "-p", "#{session_name}")
-	output, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("failed to get session name: %w", err)
+	var paneIDs []string
+	var sessionName string // Session to bring to the foreground afterwards
+	var err error
+	switch {
+	case opts.sessionName != "":
+		// Named session: attach to it if it exists, otherwise create it
+		sessionName, paneIDs, err = s.spawnNamedSession(items, layout, baseDir, opts)
+	case target == targetCurrentWindow:
+		paneIDs, err = s.spawnInCurrentSession(items, layout, baseDir)
+	case target == targetNewWindow:
+		paneIDs, err = s.spawnInNewWindow(items, layout, baseDir)
+	default:
+		sessionName, paneIDs, err = s.spawnNewSession(items, layout, baseDir)
 	}
-	sessionName := strings.TrimSpace(string(output))
 
-	// Get current window index
-	cmd = exec.Command("tmux", "display-message", "-p", "#{window_index}")
-	output, err = cmd.Output()
+	// paneIDs come back in item order (a failed launch returns a prefix)
+	msg := spawnCompleteMsg{err: err, paneIDs: paneIDs, paneItems: items[:len(paneIDs)]}
 	if err != nil {
-		return fmt.Errorf("failed to get window index: %w", err)
+		return msg
 	}
-	windowIndex := strings.TrimSpace(string(output))
-
-	target := sessionName + ":" + windowIndex
 
-	// Strategy: Create all panes first, then apply layout
-	// (This is the tmuxplexer pattern - don't track pane indices!)
+	if s.dryRun {
+		s.planStages(paneIDs, items)
+	} else {
+		msg.stages = startStages(paneIDs, items)
+	}
 
-	// First pane: send command to current pane
-	if items[0].Command != "" {
-		cwd := items[0].Cwd
-		if cwd == "" {
-			cwd = baseDir
+	// A detached target only makes sure the session exists
+	if sessionName != "" && target != targetNewSession {
+		if insideTmux() || s.dryRun {
+			msg.err = s.attachSession(sessionName)
+		} else {
+			// tmux attach needs the terminal, which only the UI can hand over
+			msg.attach = sessionName
 		}
+	}
+	return msg
+}
 
-		// Change directory and run command
-		if err := tmuxSendKeys(target+".0", fmt.Sprintf("cd '%s' && %s", cwd, items[0].Command)); err != nil {
-			return fmt.Errorf("failed to send keys to pane 0: %w", err)
-		}
+// spawnInCurrentSession spawns items as new panes in the launcher's own window
+// Every item gets a fresh split, so nothing is typed into the launcher's pane
+func (s *spawner) spawnInCurrentSession(items []launchItem, layout tmuxLayout, baseDir string) ([]string, error) {
+	// $TMUX_PANE is the launcher's pane; resolve its window by id so a
+	// focus change while we're splitting can't redirect the panes elsewhere
+	launcherPane := os.Getenv("TMUX_PANE")
+	args := []string{"display-message", "-p"}
+	if launcherPane != "" {
+		args = append(args, "-t", launcherPane)
+	}
+	args = append(args, "#{window_id} #{pane_id}")
+	output, err := s.tmuxOutput(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current window: %w", err)
 	}
+	windowID, anchorPane, _ := strings.Cut(output, " ")
 
-	// Create remaining panes
-	for i := 1; i < len(items); i++ {
-		cwd := items[i].Cwd
-		if cwd == "" {
-			cwd = baseDir
-		}
+	return s.fillWindow(windowID, anchorPane, items, layout, baseDir)
+}
 
-		// Create pane with working directory
-		cmd := exec.Command("tmux", "split-window", "-t", target, "-c", cwd)
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("failed to create pane %d: %w", i, err)
-		}
+// spawnInNewWindow creates a new window in the current session holding all items
+func (s *spawner) spawnInNewWindow(items []launchItem, layout tmuxLayout, baseDir string) ([]string, error) {
+	firstDir := items[0].Cwd
+	if firstDir == "" {
+		firstDir = baseDir
+	}
 
-		// Small delay for stability (tmuxplexer uses 10ms)
-		time.Sleep(10 * time.Millisecond)
+	// -P -F prints the new window's ids so we never have to guess indices
+	args := []string{"new-window", "-P", "-F", "#{window_id} #{pane_id}", "-n", items[0].Name, "-c", firstDir}
+	output, err := s.tmuxOutput(append(args, paneArgs(items[0])...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create window: %w", err)
 	}
+	windowID, firstPane, _ := strings.Cut(output, " ")
 
-	// Apply selected layout
-	layoutStr := layout.String()
-	cmd = exec.Command("tmux", "select-layout", "-t", target, layoutStr)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to apply layout %s: %w", layoutStr, err)
+	return s.populateWindow(windowID, firstPane, items, layout, baseDir)
+}
+
+// spawnNewSession creates a new detached tmux session with multiple panes
+// Returns the generated session name so the caller can attach to it
+func (s *spawner) spawnNewSession(items []launchItem, layout tmuxLayout, baseDir string) (string, []string, error) {
+	// Generate unique session name
+	sessionName := generateSessionName(items[0].Name)
+
+	paneIDs, err := s.buildSession(sessionName, items, layout, baseDir)
+	return sessionName, paneIDs, err
+}
+
+// spawnNamedSession makes sure a fixed session name exists, creating it when missing
+// What happens to an existing session is decided by opts.onExists
+func (s *spawner) spawnNamedSession(items []launchItem, layout tmuxLayout, baseDir string, opts batchOptions) (string, []string, error) {
+	// The name is used as configured so tmux attach -t and other tools find it
+	sessionName := opts.sessionName
+	if err := checkSessionName(sessionName); err != nil {
+		return "", nil, err
 	}
 
-	// Send commands to panes (after layout is set)
-	for i := 1; i < len(items); i++ {
-		if items[i].Command != "" {
-			paneTarget := fmt.Sprintf("%s.%d", target, i)
-			if err := tmuxSendKeys(paneTarget, items[i].Command); err != nil {
-				return fmt.Errorf("failed to send keys to pane %d: %w", i, err)
+	if tmuxHasSession(sessionName) {
+		switch opts.onExists {
+		case sessionRecreate:
+			if err := s.tmuxRun("kill-session", "-t", "="+sessionName); err != nil {
+				return "", nil, fmt.Errorf("failed to kill session %s: %w", sessionName, err)
 			}
+		case sessionNew:
+			sessionName = uniqueSessionName(sessionName)
+		default:
+			// Idempotent launch: just go to the running session
+			s.note("session " + sessionName + " already exists; attaching to it")
+			return sessionName, nil, nil
 		}
 	}
 
-	return nil
+	paneIDs, err := s.buildSession(sessionName, items, layout, baseDir)
+	return sessionName, paneIDs, err
 }
 
-// spawnNewSession creates a new tmux session with multiple panes
-func spawnNewSession(items []launchItem, layout tmuxLayout, baseDir string) error {
-	// Generate unique session name
-	sessionName := generateSessionName(items[0].Name)
-
+// buildSession creates a detached tmux session containing one pane per item
+func (s *spawner) buildSession(sessionName string, items []launchItem, layout tmuxLayout, baseDir string) ([]string, error) {
 	// Get working directory for first pane
 	firstDir := items[0].Cwd
 	if firstDir == "" {
 		firstDir = baseDir
 	}
 
-	// Create new session (detached)
-	cmd := exec.Command("tmux", "new-session", "-d", "-s", sessionName, "-c", firstDir)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to create session: %w", err)
+	// Create new session (detached), printing the ids of its first window and pane
+	args := []string{"new-session", "-d", "-s", sessionName, "-c", firstDir, "-P", "-F", "#{window_id} #{pane_id}"}
+	output, err := s.tmuxOutput(append(args, paneArgs(items[0])...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
+	windowID, firstPane, _ := strings.Cut(output, " ")
+
+	return s.populateWindow(windowID, firstPane, items, layout, baseDir)
+}
 
-	// Send command to first pane
-	if items[0].Command != "" {
-		if err := tmuxSendKeys(sessionName+":0.0", items[0].Command); err != nil {
-			return err
+// populateWindow runs items[0] in a window's existing first pane and splits the rest in
+func (s *spawner) populateWindow(windowID, firstPane string, items []launchItem, layout tmuxLayout, baseDir string) ([]string, error) {
+	// Send command to first pane (process mode already started it)
+	if items[0].Command != "" && items[0].RunAs == runKeys && !isDeferred(items[0]) {
+		if err := s.tmuxSendKeys(firstPane, items[0].Command); err != nil {
+			return []string{firstPane}, fmt.Errorf("failed to send keys to pane %s: %w", firstPane, err)
 		}
 	}
 
-	// Create remaining panes (tmuxplexer pattern)
-	target := sessionName + ":0"
-	for i := 1; i < len(items); i++ {
-		cwd := items[i].Cwd
+	paneIDs, err := s.fillWindow(windowID, firstPane, items[1:], layout, baseDir)
+	return append([]string{firstPane}, paneIDs...), err
+}
+
+// fillWindow splits one pane per item off anchorPane, then applies the layout to windowID
+// Each split reports its #{pane_id}, and every later command targets that id,
+// so existing panes, base-index settings and concurrent splits don't matter
+func (s *spawner) fillWindow(windowID, anchorPane string, items []launchItem, layout tmuxLayout, baseDir string) ([]string, error) {
+	var paneIDs []string
+
+	// Strategy: Create all panes first, then apply layout
+	// (This is the tmuxplexer pattern - don't track pane indices!)
+	lastPane := anchorPane
+	for i, item := range items {
+		cwd := item.Cwd
 		if cwd == "" {
 			cwd = baseDir
 		}
 
-		cmd := exec.Command("tmux", "split-window", "-t", target, "-c", cwd)
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("failed to create pane %d: %w", i, err)
+		// Create pane with working directory, splitting the pane we created last
+		args := []string{"split-window", "-t", lastPane, "-c", cwd, "-P", "-F", "#{pane_id}"}
+		paneID, err := s.tmuxOutput(append(args, paneArgs(item)...)...)
+		if err != nil {
+			return paneIDs, fmt.Errorf("failed to create pane %d: %w", i+1, err)
 		}
+		paneIDs = append(paneIDs, paneID)
+		lastPane = paneID
 
-		time.Sleep(10 * time.Millisecond)
+		// Small delay so the shell is up before we type into it (tmuxplexer uses 10ms)
+		if item.RunAs == runKeys {
+			s.sleep(10 * time.Millisecond)
+		}
 	}
 
-	// Apply layout
+	// Apply selected layout
 	layoutStr := layout.String()
-	cmd = exec.Command("tmux", "select-layout", "-t", target, layoutStr)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to apply layout: %w", err)
+	if err := s.tmuxRun("select-layout", "-t", windowID, layoutStr); err != nil {
+		return paneIDs, fmt.Errorf("failed to apply layout %s: %w", layoutStr, err)
 	}
 
-	// Send commands to remaining panes
-	for i := 1; i < len(items); i++ {
-		if items[i].Command != "" {
-			paneTarget := fmt.Sprintf("%s:0.%d", sessionName, i)
-			if err := tmuxSendKeys(paneTarget, items[i].Command); err != nil {
-				return err
+	// Send commands to keys-mode panes (after layout is set)
+	for i, item := range items {
+		if item.Command != "" && item.RunAs == runKeys && !isDeferred(item) {
+			if err := s.tmuxSendKeys(paneIDs[i], item.Command); err != nil {
+				return paneIDs, fmt.Errorf("failed to send keys to pane %s: %w", paneIDs[i], err)
 			}
 		}
 	}
 
-	// Attach to session
-	cmd = exec.Command("tmux", "attach", "-t", sessionName)
-	return cmd.Run()
+	return paneIDs, nil
+}
+
+// attachSession brings a session to the foreground
+// Inside tmux we switch the client instead of nesting a second tmux attach;
+// outside tmux only dry runs get here (the UI attaches with attachSessionCmd)
+func (s *spawner) attachSession(sessionName string) error {
+	if insideTmux() {
+		return s.tmuxRun("switch-client", "-t", "="+sessionName)
+	}
+	s.record(planStep{args: []string{"tmux", "attach", "-t", "=" + sessionName}})
+	return nil
+}
+
+// attachSessionCmd attaches the terminal to a session outside tmux
+// The launcher suspends and comes back once the user detaches
+func attachSessionCmd(sessionName string) tea.Cmd {
+	cmd := exec.Command("tmux", "attach", "-t", "="+sessionName)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return attachDoneMsg{session: sessionName, err: err}
+	})
+}
+
+// tmuxHasSession reports whether a session with exactly this name exists
+// The "=" prefix disables tmux's prefix matching on session names
+// It only queries tmux, so dry runs use it too
+func tmuxHasSession(sessionName string) bool {
+	cmd := exec.Command("tmux", "has-session", "-t", "="+sessionName)
+	return cmd.Run() == nil
 }
 
 // tmuxSplitHorizontal splits the current pane horizontally
-func tmuxSplitHorizontal(item launchItem) error {
+func (s *spawner) tmuxSplitHorizontal(item launchItem) (string, error) {
 	cwd := item.Cwd
 	if cwd == "" {
 		cwd = os.Getenv("HOME")
 	}
 
 	// Use shell to properly execute the command
-	cmd := exec.Command("tmux", "split-window", "-h", "-c", cwd, "sh", "-c", item.Command)
-	return cmd.Run()
+	return s.tmuxOutput(append([]string{"split-window", "-h", "-c", cwd, "-P", "-F", "#{pane_id}", "sh", "-c", item.Command},
+		remainOnExitArgs(item)...)...)
 }
 
 // tmuxSplitVertical splits the current pane vertically
-func tmuxSplitVertical(item launchItem) error {
+func (s *spawner) tmuxSplitVertical(item launchItem) (string, error) {
 	cwd := item.Cwd
 	if cwd == "" {
 		cwd = os.Getenv("HOME")
 	}
 
 	// Use shell to properly execute the command
-	cmd := exec.Command("tmux", "split-window", "-v", "-c", cwd, "sh", "-c", item.Command)
-	return cmd.Run()
+	return s.tmuxOutput(append([]string{"split-window", "-v", "-c", cwd, "-P", "-F", "#{pane_id}", "sh", "-c", item.Command},
+		remainOnExitArgs(item)...)...)
 }
 
 // tmuxNewWindow creates a new tmux window
-func tmuxNewWindow(item launchItem) error {
+func (s *spawner) tmuxNewWindow(item launchItem) (string, error) {
 	cwd := item.Cwd
 	if cwd == "" {
 		cwd = os.Getenv("HOME")
 	}
 
 	// Use shell to properly execute the command
-	cmd := exec.Command("tmux", "new-window", "-c", cwd, "-n", item.Name, "sh", "-c", item.Command)
-	return cmd.Run()
+	return s.tmuxOutput(append([]string{"new-window", "-c", cwd, "-n", item.Name, "-P", "-F", "#{pane_id}", "sh", "-c", item.Command},
+		remainOnExitArgs(item)...)...)
 }
 
 // tmuxCurrentPane runs command in current pane
-func tmuxCurrentPane(item launchItem) error {
+func (s *spawner) tmuxCurrentPane(item launchItem) (string, error) {
 	cwd := item.Cwd
 	if cwd == "" {
 		cwd = os.Getenv("HOME")
@@ -254,11 +370,12 @@ func tmuxCurrentPane(item launchItem) error {
 
 	// Change directory and run command
 	commandStr := fmt.Sprintf("cd '%s' && %s", cwd, item.Command)
-	return tmuxSendKeys("", commandStr)
+	paneID := os.Getenv("TMUX_PANE")
+	return paneID, s.tmuxSendKeys(paneID, commandStr)
 }
 
 // xtermWindow spawns a new xterm window
-func xtermWindow(item launchItem) error {
+func (s *spawner) xtermWindow(item launchItem) (*exec.Cmd, error) {
 	cwd := item.Cwd
 	if cwd == "" {
 		cwd = os.Getenv("HOME")
@@ -266,43 +383,149 @@ func xtermWindow(item launchItem) error {
 
 	// Use shell -c to run cd + command
 	shellCmd := fmt.Sprintf("cd '%s' && %s", cwd, item.Command)
-	cmd := exec.Command("xterm", "-e", "sh", "-c", shellCmd)
+	args := []string{"xterm", "-e", "sh", "-c", shellCmd}
+
+	if s.dryRun {
+		// Runs in the background; the script must not wait for it
+		s.record(planStep{args: args, background: true})
+		return nil, nil
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
 
 	// Start in background
 	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to spawn xterm: %w", err)
+		return nil, fmt.Errorf("failed to spawn xterm: %w", err)
 	}
 
-	return nil
+	return cmd, nil
 }
 
 // tmuxSendKeys sends keys to a tmux pane (with Enter)
-func tmuxSendKeys(target, keys string) error {
+func (s *spawner) tmuxSendKeys(target, keys string) error {
 	args := []string{"send-keys"}
 	if target != "" {
 		args = append(args, "-t", target)
 	}
 	args = append(args, keys, "C-m") // C-m = Enter
 
+	return s.tmuxRun(args...)
+}
+
+// paneArgs returns the trailing arguments for the tmux command that creates item's pane
+// Items waiting on depends_on get an idle pane; the stage runner starts them later
+func paneArgs(item launchItem) []string {
+	if isDeferred(item) {
+		return remainOnExitArgs(item)
+	}
+	return processArgs(item)
+}
+
+// processArgs returns the trailing new-session/new-window/split-window arguments
+// that start item as the pane's own process (run_as: process), or nil for keys mode
+func processArgs(item launchItem) []string {
+	if item.RunAs != runProcess || item.Command == "" {
+		return nil
+	}
+	return append([]string{"sh", "-c", item.Command}, remainOnExitArgs(item)...)
+}
+
+// remainOnExitArgs chains "; set-option -p remain-on-exit on" onto a pane-creating
+// command for run_as: process, so a failed command leaves its pane and exit status visible
+// Both commands run in one tmux command queue, so the pane can't die before the option is set
+func remainOnExitArgs(item launchItem) []string {
+	if item.RunAs != runProcess {
+		return nil
+	}
+	return []string{";", "set-option", "-p", "remain-on-exit", "on"}
+}
+
+// tmuxOutput runs a tmux command and returns its trimmed stdout
+// A dry run records the command and returns placeholders for the ids it would print
+func (s *spawner) tmuxOutput(args ...string) (string, error) {
+	if s.dryRun {
+		return s.recordTmux(args), nil
+	}
+	return tmuxOutput(args...)
+}
+
+// tmuxRun runs a tmux command for its side effect (or records it in a dry run)
+func (s *spawner) tmuxRun(args ...string) error {
+	_, err := s.tmuxOutput(args...)
+	return err
+}
+
+// sleep pauses a live spawn; dry runs don't wait
+func (s *spawner) sleep(d time.Duration) {
+	if !s.dryRun {
+		time.Sleep(d)
+	}
+}
+
+// tmuxOutput runs a tmux command and returns its trimmed stdout
+// Failures come back as *tmuxError carrying tmux's stderr
+func tmuxOutput(args ...string) (string, error) {
 	cmd := exec.Command("tmux", args...)
-	return cmd.Run()
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", &tmuxError{args: args, stderr: strings.TrimSpace(stderr.String()), err: err}
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// tmuxRun runs a tmux command for its side effect, keeping stderr on failure
+func tmuxRun(args ...string) error {
+	_, err := tmuxOutput(args...)
+	return err
 }
 
 // generateSessionName creates a unique session name
 func generateSessionName(baseName string) string {
+	cleaned := sanitizeSessionName(baseName)
+
+	// Add timestamp, then a suffix if another launch used the same second
+	timestamp := time.Now().Format("150405") // HHMMSS
+	return uniqueSessionName(fmt.Sprintf("%s-%s", cleaned, timestamp))
+}
+
+// checkSessionName rejects session names tmux can't use
+// "." and ":" separate the window and pane in tmux targets
+func checkSessionName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("session name is empty")
+	}
+	if strings.ContainsAny(name, ".:") {
+		return fmt.Errorf("session name %q can't contain \".\" or \":\"", name)
+	}
+	return nil
+}
+
+// sanitizeSessionName lowercases a name and keeps only characters tmux accepts everywhere
+func sanitizeSessionName(baseName string) string {
 	// Clean the name
 	cleaned := strings.ToLower(baseName)
 	cleaned = strings.ReplaceAll(cleaned, " ", "-")
 
 	// Remove special characters (keep alphanumeric and hyphens)
-	cleaned = strings.Map(func(r rune) rune {
+	return strings.Map(func(r rune) rune {
 		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
 			return r
 		}
 		return -1
 	}, cleaned)
+}
 
-	// Add timestamp to ensure uniqueness
-	timestamp := time.Now().Format("150405") // HHMMSS
-	return fmt.Sprintf("%s-%s", cleaned, timestamp)
+// uniqueSessionName appends -2, -3, ... until the name is not taken
+func uniqueSessionName(name string) string {
+	if !tmuxHasSession(name) {
+		return name
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", name, i)
+		if !tmuxHasSession(candidate) {
+			return candidate
+		}
+	}
 }
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stages.go - depends_on / ready_when staging for batch and profile launches
+// All panes are created up front (so the layout is right), but panes with
+// depends_on stay idle until every dependency passes its ready_when check
+
+const (
+	defaultReadyTimeout = 60 * time.Second
+	readyPollInterval   = 500 * time.Millisecond
+)
+
+// isDeferred reports whether an item's command must wait for its dependencies
+func isDeferred(item launchItem) bool {
+	return len(item.DependsOn) > 0
+}
+
+// validateStages checks ready_when settings and rejects dependency cycles
+// depends_on names that aren't part of this launch are treated as already ready;
+// a name it refers to must belong to only one item of the launch
+func validateStages(items []launchItem) error {
+	names := make(map[string]int)
+	shared := make(map[string]bool)
+	for i, item := range items {
+		if _, ok := names[item.Name]; ok {
+			shared[item.Name] = true
+		}
+		names[item.Name] = i
+	}
+	for _, item := range items {
+		for _, dep := range item.DependsOn {
+			if shared[dep] {
+				return fmt.Errorf("duplicate item name %q in launch (%s depends on it)", dep, item.Name)
+			}
+		}
+	}
+
+	for _, item := range items {
+		if item.ReadyWhen == nil {
+			continue
+		}
+		if _, err := readyTimeout(item.ReadyWhen); err != nil {
+			return fmt.Errorf("%s: %w", item.Name, err)
+		}
+		if item.ReadyWhen.Log != "" {
+			if _, err := regexp.Compile(item.ReadyWhen.Log); err != nil {
+				return fmt.Errorf("%s: invalid ready_when log pattern: %w", item.Name, err)
+			}
+		}
+	}
+
+	// Depth-first search for cycles (0 = unvisited, 1 = in progress, 2 = done)
+	visited := make([]int, len(items))
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch visited[i] {
+		case 1:
+			return fmt.Errorf("dependency cycle involving %s", items[i].Name)
+		case 2:
+			return nil
+		}
+		visited[i] = 1
+		for _, dep := range items[i].DependsOn {
+			if j, ok := names[dep]; ok {
+				if err := visit(j); err != nil {
+					return err
+				}
+			}
+		}
+		visited[i] = 2
+		return nil
+	}
+	for i := range items {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readyTimeout parses ready_when.timeout
+func readyTimeout(rc *readyConfig) (time.Duration, error) {
+	if rc == nil || rc.Timeout == "" {
+		return defaultReadyTimeout, nil
+	}
+	d, err := time.ParseDuration(rc.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ready_when timeout %q: %w", rc.Timeout, err)
+	}
+	return d, nil
+}
+
+// startStages runs staging in the background when any item needs it
+// Returns nil when every pane was started directly
+func startStages(paneIDs []string, items []launchItem) <-chan stageMsg {
+	items = items[:len(paneIDs)]
+
+	needed := false
+	for _, item := range items {
+		if isDeferred(item) || item.ReadyWhen != nil {
+			needed = true
+		}
+	}
+	if !needed {
+		return nil
+	}
+
+	// Buffered so staging never blocks on a slow UI
+	ch := make(chan stageMsg, len(items)*4)
+	go runStages(paneIDs, items, ch)
+	return ch
+}
+
+// runStages starts deferred panes as their dependencies become ready
+// A pane is ready once it started and its ready_when (if any) holds
+func runStages(paneIDs []string, items []launchItem, ch chan<- stageMsg) {
+	defer close(ch)
+
+	names := make(map[string]int)
+	for i, item := range items {
+		names[item.Name] = i
+	}
+
+	states := make([]processState, len(items))
+	startedAt := make([]time.Time, len(items))
+
+	send := func(i int, state processState, detail string) {
+		states[i] = state
+		ch <- stageMsg{paneID: paneIDs[i], state: state, detail: detail}
+	}
+
+	// started marks a pane as running its command and decides whether to check readiness
+	started := func(i int) {
+		startedAt[i] = time.Now()
+		if items[i].ReadyWhen != nil {
+			send(i, stateStarting, "checking ready_when")
+		} else {
+			send(i, stateRunning, "")
+		}
+	}
+
+	for i, item := range items {
+		if isDeferred(item) {
+			send(i, stateWaiting, "waiting for "+strings.Join(item.DependsOn, ", "))
+		} else {
+			started(i)
+		}
+	}
+
+	for {
+		pending := false
+
+		for i, item := range items {
+			switch states[i] {
+			case stateWaiting:
+				ready, failedDep := true, ""
+				for _, dep := range item.DependsOn {
+					j, ok := names[dep]
+					if !ok {
+						continue // Not part of this launch
+					}
+					if states[j] == stateFailed {
+						failedDep = dep
+						break
+					}
+					if states[j] != stateRunning {
+						ready = false
+					}
+				}
+
+				switch {
+				case failedDep != "":
+					send(i, stateFailed, "dependency "+failedDep+" failed")
+				case ready:
+					if err := liveSpawner.startDeferredPane(paneIDs[i], item); err != nil {
+						send(i, stateFailed, err.Error())
+					} else {
+						started(i)
+					}
+				default:
+					pending = true
+				}
+
+			case stateStarting:
+				timeout, _ := readyTimeout(item.ReadyWhen)
+				switch {
+				case checkReady(paneIDs[i], item):
+					send(i, stateRunning, "ready")
+				case time.Since(startedAt[i]) > timeout:
+					send(i, stateFailed, fmt.Sprintf("not ready after %s", timeout))
+				default:
+					pending = true
+				}
+			}
+		}
+
+		if !pending {
+			return
+		}
+		time.Sleep(readyPollInterval)
+	}
+}
+
+// startDeferredPane starts an item in the idle pane created for it
+func (s *spawner) startDeferredPane(paneID string, item launchItem) error {
+	if item.Command == "" {
+		return nil
+	}
+	if item.RunAs == runProcess {
+		// The pane already has remain-on-exit; replace its idle shell with the command
+		return s.respawnPane(paneID, item)
+	}
+	return s.tmuxSendKeys(paneID, item.Command)
+}
+
+// checkReady reports whether every ready_when condition holds for a pane
+func checkReady(paneID string, item launchItem) bool {
+	rc := item.ReadyWhen
+	if rc == nil {
+		return true
+	}
+
+	if rc.Port != 0 {
+		host := rc.Host
+		if host == "" {
+			host = "localhost"
+		}
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(rc.Port)), readyPollInterval)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+	}
+
+	if rc.File != "" {
+		path := expandPath(rc.File)
+		if !filepath.IsAbs(path) && item.Cwd != "" {
+			path = filepath.Join(item.Cwd, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return false
+		}
+	}
+
+	if rc.Log != "" {
+		// -J joins wrapped lines so long log lines match as one
+		output, err := tmuxOutput("capture-pane", "-p", "-J", "-t", paneID, "-S", "-1000")
+		if err != nil {
+			return false
+		}
+		if matched, _ := regexp.MatchString(rc.Log, output); !matched {
+			return false
+		}
+	}
+
+	if rc.Command != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, "sh", "-c", rc.Command)
+		cmd.Dir = item.Cwd
+		if err := cmd.Run(); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// waitForStage delivers the next staging update to Update
+// Returns nil once the stage runner is done
+func waitForStage(ch <-chan stageMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-ch
+		if !ok {
+			return nil
+		}
+		msg.stages = ch
+		return msg
+	}
+}
+
+// applyStage updates the tracked pane a staging message refers to
+func (m *model) applyStage(msg stageMsg) {
+	for path, tracked := range m.tracked {
+		for i, tp := range tracked {
+			if tp.paneID == msg.paneID {
+				tracked[i].state = msg.state
+				tracked[i].detail = msg.detail
+			}
+		}
+		m.tracked[path] = tracked
+	}
+}
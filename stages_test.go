@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateStages(t *testing.T) {
+	pane := func(name string, deps ...string) launchItem {
+		return launchItem{Name: name, DependsOn: deps}
+	}
+	ready := func(item launchItem, rc readyConfig) launchItem {
+		item.ReadyWhen = &rc
+		return item
+	}
+
+	tests := []struct {
+		name    string
+		items   []launchItem
+		wantErr string // "" = valid
+	}{
+		{"no panes", nil, ""},
+		{"independent panes", []launchItem{pane("db"), pane("app")}, ""},
+		{"chain", []launchItem{pane("db"), pane("api", "db"), pane("web", "api")}, ""},
+		{"diamond", []launchItem{pane("db"), pane("a", "db"), pane("b", "db"), pane("web", "a", "b")}, ""},
+		{"dependency declared later", []launchItem{pane("web", "db"), pane("db")}, ""},
+		{"missing dependency is treated as ready", []launchItem{pane("web", "elsewhere")}, ""},
+		{"self dependency", []launchItem{pane("web", "web")}, "dependency cycle involving web"},
+		{"two-pane cycle", []launchItem{pane("a", "b"), pane("b", "a")}, "dependency cycle"},
+		{"cycle behind a valid pane", []launchItem{pane("db"), pane("a", "db", "c"), pane("b", "a"), pane("c", "b")}, "dependency cycle"},
+		{"duplicate names nobody depends on", []launchItem{pane("htop"), pane("htop"), pane("web", "db"), pane("db")}, ""},
+		{"dependency on a duplicate name", []launchItem{pane("db"), pane("db"), pane("web", "db")}, `duplicate item name "db" in launch (web depends on it)`},
+		{"duplicate hides a cycle", []launchItem{pane("a", "b"), pane("b"), pane("b", "a")}, `duplicate item name "b" in launch`},
+		{"valid ready_when", []launchItem{ready(pane("db"), readyConfig{Port: 5432, Timeout: "10s", Log: `listening on \d+`})}, ""},
+		{"bad timeout", []launchItem{ready(pane("db"), readyConfig{Timeout: "soon"})}, `db: invalid ready_when timeout "soon"`},
+		{"bad log pattern", []launchItem{ready(pane("db"), readyConfig{Log: "ready ("})}, "db: invalid ready_when log pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStages(tt.items)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && err == nil:
+				t.Fatalf("expected an error containing %q", tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Fatalf("error %q doesn't contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadyTimeout(t *testing.T) {
+	tests := []struct {
+		rc      *readyConfig
+		want    string
+		wantErr bool
+	}{
+		{nil, defaultReadyTimeout.String(), false},
+		{&readyConfig{}, defaultReadyTimeout.String(), false},
+		{&readyConfig{Timeout: "90s"}, "1m30s", false},
+		{&readyConfig{Timeout: "ten"}, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := readyTimeout(tt.rc)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("readyTimeout(%+v) error = %v, want error %v", tt.rc, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.String() != tt.want {
+			t.Errorf("readyTimeout(%+v) = %s, want %s", tt.rc, got, tt.want)
+		}
+	}
+}
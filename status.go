@@ -0,0 +1,356 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// status.go - Tracking and controlling processes started by launches
+// tmux panes are polled with list-panes; xterm processes are waited on
+
+// statusTick schedules the next poll of tracked panes
+func statusTick() tea.Cmd {
+	return tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
+		return statusTickMsg{}
+	})
+}
+
+// pollPaneStatus reads the state of every pane on the tmux server
+// Panes launched with run_as: process stay around dead (remain-on-exit),
+// which is what makes their exit status visible here
+func pollPaneStatus() tea.Msg {
+	output, err := tmuxOutput("list-panes", "-a", "-F", "#{pane_id} #{pane_dead} #{pane_dead_status}")
+	if err != nil {
+		return paneStatusMsg{err: err}
+	}
+
+	panes := make(map[string]trackedPane)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		pane := trackedPane{paneID: fields[0], state: stateRunning, exitCode: -1}
+		if fields[1] == "1" {
+			pane.state = stateCrashed
+			if len(fields) > 2 {
+				if code, err := strconv.Atoi(fields[2]); err == nil {
+					pane.exitCode = code
+					if code == 0 {
+						pane.state = stateExited
+					}
+				}
+			}
+		}
+		panes[pane.paneID] = pane
+	}
+
+	return paneStatusMsg{panes: panes}
+}
+
+// pollTick is pollPaneStatus for the statusTick loop
+func pollTick() tea.Msg {
+	msg := pollPaneStatus().(paneStatusMsg)
+	msg.fromTick = true
+	return msg
+}
+
+// waitProcess waits for an xterm process and reports how it ended
+func waitProcess(cmd *exec.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		err := cmd.Wait()
+		return processExitedMsg{pid: cmd.Process.Pid, exitCode: exitCodeOf(err)}
+	}
+}
+
+// exitCodeOf converts a Wait error into an exit code (-1 for signals/unknown)
+func exitCodeOf(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// trackLaunch records the panes/process a spawn created, keyed by item path
+// A new launch of the same item replaces what was tracked before
+func (m *model) trackLaunch(msg spawnCompleteMsg) tea.Cmd {
+	var cmds []tea.Cmd
+
+	launched := make(map[string][]trackedPane)
+	for i, paneID := range msg.paneIDs {
+		item := msg.paneItems[i]
+		launched[item.Path] = append(launched[item.Path], trackedPane{
+			paneID:   paneID,
+			item:     item,
+			state:    stateRunning,
+			exitCode: -1,
+		})
+	}
+	if msg.proc != nil {
+		launched[msg.procItem.Path] = append(launched[msg.procItem.Path], trackedPane{
+			proc:     msg.proc.Process,
+			item:     msg.procItem,
+			state:    stateRunning,
+			exitCode: -1,
+		})
+		cmds = append(cmds, waitProcess(msg.proc))
+	}
+
+	for path, panes := range launched {
+		if path == "" {
+			continue
+		}
+		m.tracked[path] = panes
+	}
+
+	if len(msg.paneIDs) > 0 && !m.statusPolling {
+		m.statusPolling = true
+		cmds = append(cmds, statusTick())
+	}
+	if msg.stages != nil {
+		cmds = append(cmds, waitForStage(msg.stages))
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// applyPaneStatus updates tracked tmux panes from a poll
+// Panes missing from the server were closed, so they count as exited
+// A live pane keeps its staging state (waiting/starting/failed)
+func (m *model) applyPaneStatus(panes map[string]trackedPane) {
+	for path, tracked := range m.tracked {
+		for i, tp := range tracked {
+			if tp.paneID == "" {
+				continue
+			}
+			current, ok := panes[tp.paneID]
+			switch {
+			case !ok:
+				if tp.state != stateExited && tp.state != stateCrashed {
+					tracked[i].state = stateExited
+				}
+			case current.state != stateRunning || tp.state == stateRunning:
+				tracked[i].state = current.state
+				tracked[i].exitCode = current.exitCode
+			}
+		}
+		m.tracked[path] = tracked
+	}
+}
+
+// applyProcessExit updates the tracked xterm process with this pid
+func (m *model) applyProcessExit(pid, exitCode int) {
+	for path, tracked := range m.tracked {
+		for i, tp := range tracked {
+			if tp.proc == nil || tp.proc.Pid != pid {
+				continue
+			}
+			tracked[i].exitCode = exitCode
+			if exitCode == 0 {
+				tracked[i].state = stateExited
+			} else {
+				tracked[i].state = stateCrashed
+			}
+		}
+		m.tracked[path] = tracked
+	}
+}
+
+// hasRunningPanes reports whether any tracked tmux pane still needs polling
+func (m model) hasRunningPanes() bool {
+	for _, tracked := range m.tracked {
+		for _, tp := range tracked {
+			if tp.paneID != "" && isAlive(tp.state) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isAlive reports whether a state can still change on its own
+func isAlive(state processState) bool {
+	return state == stateRunning || state == stateWaiting || state == stateStarting
+}
+
+// aggregateState summarizes an item's panes by priority:
+// crashed, failed, waiting, starting, running, then exited
+func aggregateState(tracked []trackedPane) (processState, int) {
+	priority := map[processState]int{
+		stateCrashed:  5,
+		stateFailed:   4,
+		stateWaiting:  3,
+		stateStarting: 2,
+		stateRunning:  1,
+		stateExited:   0,
+	}
+
+	state, code := stateExited, 0
+	for i, tp := range tracked {
+		if i == 0 || priority[tp.state] > priority[state] {
+			state, code = tp.state, tp.exitCode
+		}
+	}
+	return state, code
+}
+
+// statusBadge renders the status shown beside an item in the tree ("" if never launched)
+func (m model) statusBadge(path string) string {
+	tracked := m.tracked[path]
+	if len(tracked) == 0 {
+		return ""
+	}
+
+	state, code := aggregateState(tracked)
+	switch state {
+	case stateRunning:
+		return m.icons.running
+	case stateWaiting, stateStarting:
+		return m.icons.waiting + " " + state.String()
+	case stateFailed:
+		return m.icons.crashed + " failed"
+	case stateCrashed:
+		if code < 0 {
+			return m.icons.crashed + " crashed"
+		}
+		return fmt.Sprintf("%s crashed(%d)", m.icons.crashed, code)
+	default:
+		if code < 0 {
+			return m.icons.stopped + " exited"
+		}
+		return fmt.Sprintf("%s exited(%d)", m.icons.stopped, code)
+	}
+}
+
+// statusStyle is the theme style for an item's process status badge
+func (m model) statusStyle(path string) lipgloss.Style {
+	state, _ := aggregateState(m.tracked[path])
+	switch state {
+	case stateRunning:
+		return m.theme.running
+	case stateWaiting, stateStarting:
+		return m.theme.waiting
+	case stateFailed, stateCrashed:
+		return m.theme.crashed
+	default:
+		return m.theme.stopped
+	}
+}
+
+// treeBadge is everything shown after an item's name in the tree:
+// process status for launched items, git status for projects
+func (m model) treeBadge(path string) string {
+	badges := []string{}
+	for _, badge := range []string{m.gitBadge(path), m.statusBadge(path)} {
+		if badge != "" {
+			badges = append(badges, badge)
+		}
+	}
+	return strings.Join(badges, " ")
+}
+
+// focusTracked brings the first tracked pane of an item to the foreground
+func focusTracked(tracked []trackedPane) tea.Cmd {
+	return func() tea.Msg {
+		for _, tp := range tracked {
+			if tp.paneID == "" {
+				continue
+			}
+			if err := tmuxRun("select-window", "-t", tp.paneID); err != nil {
+				return spawnCompleteMsg{err: fmt.Errorf("failed to focus pane %s: %w", tp.paneID, err)}
+			}
+			if err := tmuxRun("select-pane", "-t", tp.paneID); err != nil {
+				return spawnCompleteMsg{err: fmt.Errorf("failed to focus pane %s: %w", tp.paneID, err)}
+			}
+			// The pane may live in another session
+			if insideTmux() {
+				if err := tmuxRun("switch-client", "-t", tp.paneID); err != nil {
+					return spawnCompleteMsg{err: fmt.Errorf("failed to switch to pane %s: %w", tp.paneID, err)}
+				}
+			}
+			return nil
+		}
+		return spawnCompleteMsg{err: fmt.Errorf("nothing to focus (xterm windows can't be focused)")}
+	}
+}
+
+// killTracked terminates every pane/process of an item
+func killTracked(tracked []trackedPane) tea.Cmd {
+	return func() tea.Msg {
+		for _, tp := range tracked {
+			if tp.paneID != "" {
+				// Already-closed panes make kill-pane fail; that's fine
+				tmuxRun("kill-pane", "-t", tp.paneID)
+			} else if tp.proc != nil && tp.state == stateRunning {
+				tp.proc.Kill()
+			}
+		}
+		return pollPaneStatus()
+	}
+}
+
+// restartTracked restarts every pane of an item in place
+// tmux panes are respawned (keeping their position); xterm items get a new window
+func restartTracked(tracked []trackedPane) tea.Cmd {
+	return func() tea.Msg {
+		msg := spawnCompleteMsg{}
+		for _, tp := range tracked {
+			if tp.paneID == "" {
+				if tp.proc != nil && tp.state == stateRunning {
+					tp.proc.Kill()
+				}
+				proc, err := liveSpawner.xtermWindow(tp.item)
+				if err != nil {
+					msg.err = err
+					return msg
+				}
+				msg.proc, msg.procItem = proc, tp.item
+				continue
+			}
+
+			if err := liveSpawner.respawnPane(tp.paneID, tp.item); err != nil {
+				msg.err = fmt.Errorf("failed to restart pane %s: %w", tp.paneID, err)
+				return msg
+			}
+			msg.paneIDs = append(msg.paneIDs, tp.paneID)
+			msg.paneItems = append(msg.paneItems, tp.item)
+		}
+		return msg
+	}
+}
+
+// respawnPane kills whatever runs in a pane and starts the item again
+func (s *spawner) respawnPane(paneID string, item launchItem) error {
+	cwd := item.Cwd
+	if cwd == "" {
+		cwd = os.Getenv("HOME")
+	}
+
+	args := []string{"respawn-pane", "-k", "-t", paneID, "-c", cwd}
+	if item.RunAs == runProcess || item.Command == "" {
+		if item.Command != "" {
+			args = append(args, "sh", "-c", item.Command)
+		}
+		return s.tmuxRun(args...)
+	}
+
+	// Keys mode: fresh shell, then type the command again
+	if err := s.tmuxRun(args...); err != nil {
+		return err
+	}
+	s.sleep(10 * time.Millisecond)
+	return s.tmuxSendKeys(paneID, item.Command)
+}
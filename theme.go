@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// theme.go - Colors and styles
+// Built-in themes give every color for truecolor, 256-color and 16-color terminals;
+// lipgloss picks the one the terminal supports and drops colors under NO_COLOR.
+// Attributes (bold, reverse) keep the cursor and selection visible without color
+
+// color is a color with an explicit value for each terminal color depth
+func color(trueColor, ansi256, ansi string) lipgloss.TerminalColor {
+	return lipgloss.CompleteColor{TrueColor: trueColor, ANSI256: ansi256, ANSI: ansi}
+}
+
+// builtinThemes are the themes that need no config
+var builtinThemes = map[string]themeColors{
+	"dark": {
+		border:       color("#585858", "240", "8"),
+		activeBorder: color("#00afff", "39", "12"),
+		cursor:       color("#ff87d7", "212", "13"),
+		selected:     color("#00d787", "42", "10"),
+		category:     color("#5fafff", "75", "12"),
+		profile:      color("#d787ff", "177", "13"),
+		running:      color("#00d787", "42", "10"),
+		stopped:      color("#8a8a8a", "245", "7"),
+		crashed:      color("#ff5f5f", "203", "9"),
+		waiting:      color("#ffd700", "220", "11"),
+		git:          color("#87afaf", "109", "6"),
+		footerKey:    color("#bcbcbc", "250", "7"),
+		footer:       color("#808080", "244", "8"),
+		muted:        color("#585858", "240", "8"),
+		heading:      color("#eeeeee", "255", "15"),
+		success:      color("#00d787", "42", "10"),
+		failure:      color("#ff0000", "196", "9"),
+	},
+	"light": {
+		border:       color("#bcbcbc", "250", "7"),
+		activeBorder: color("#005faf", "25", "4"),
+		cursor:       color("#d7005f", "161", "5"),
+		selected:     color("#008700", "28", "2"),
+		category:     color("#005faf", "25", "4"),
+		profile:      color("#8700af", "91", "5"),
+		running:      color("#008700", "28", "2"),
+		stopped:      color("#767676", "243", "8"),
+		crashed:      color("#d70000", "160", "1"),
+		waiting:      color("#af5f00", "130", "3"),
+		git:          color("#008787", "30", "6"),
+		footerKey:    color("#303030", "236", "0"),
+		footer:       color("#6c6c6c", "242", "8"),
+		muted:        color("#949494", "246", "8"),
+		heading:      color("#080808", "232", "0"),
+		success:      color("#008700", "28", "2"),
+		failure:      color("#d70000", "160", "1"),
+	},
+	"high-contrast": {
+		border:       color("#ffffff", "15", "15"),
+		activeBorder: color("#ffff00", "11", "11"),
+		cursor:       color("#ffff00", "11", "11"),
+		selected:     color("#ffff00", "11", "11"),
+		category:     color("#00ffff", "14", "14"),
+		profile:      color("#ff00ff", "13", "13"),
+		running:      color("#00ff00", "10", "10"),
+		stopped:      color("#ffffff", "15", "15"),
+		crashed:      color("#ff0000", "9", "9"),
+		waiting:      color("#ffff00", "11", "11"),
+		git:          color("#00ffff", "14", "14"),
+		footerKey:    color("#ffff00", "11", "11"),
+		footer:       color("#ffffff", "15", "15"),
+		muted:        color("#ffffff", "15", "15"),
+		heading:      color("#ffffff", "15", "15"),
+		success:      color("#00ff00", "10", "10"),
+		failure:      color("#ff0000", "9", "9"),
+	},
+}
+
+// themeRoles maps the color names used in user themes to their field
+var themeRoles = map[string]func(c *themeColors) *lipgloss.TerminalColor{
+	"border":        func(c *themeColors) *lipgloss.TerminalColor { return &c.border },
+	"active_border": func(c *themeColors) *lipgloss.TerminalColor { return &c.activeBorder },
+	"cursor":        func(c *themeColors) *lipgloss.TerminalColor { return &c.cursor },
+	"selected":      func(c *themeColors) *lipgloss.TerminalColor { return &c.selected },
+	"category":      func(c *themeColors) *lipgloss.TerminalColor { return &c.category },
+	"profile":       func(c *themeColors) *lipgloss.TerminalColor { return &c.profile },
+	"running":       func(c *themeColors) *lipgloss.TerminalColor { return &c.running },
+	"stopped":       func(c *themeColors) *lipgloss.TerminalColor { return &c.stopped },
+	"crashed":       func(c *themeColors) *lipgloss.TerminalColor { return &c.crashed },
+	"waiting":       func(c *themeColors) *lipgloss.TerminalColor { return &c.waiting },
+	"git":           func(c *themeColors) *lipgloss.TerminalColor { return &c.git },
+	"footer_key":    func(c *themeColors) *lipgloss.TerminalColor { return &c.footerKey },
+	"footer":        func(c *themeColors) *lipgloss.TerminalColor { return &c.footer },
+	"muted":         func(c *themeColors) *lipgloss.TerminalColor { return &c.muted },
+	"heading":       func(c *themeColors) *lipgloss.TerminalColor { return &c.heading },
+	"success":       func(c *themeColors) *lipgloss.TerminalColor { return &c.success },
+	"failure":       func(c *themeColors) *lipgloss.TerminalColor { return &c.failure },
+}
+
+// autoThemeName picks dark or light from the terminal background
+// lipgloss caches the answer; main asks before Bubble Tea takes over the terminal
+func autoThemeName() string {
+	if lipgloss.HasDarkBackground() {
+		return "dark"
+	}
+	return "light"
+}
+
+// loadTheme resolves the config's theme: and themes: settings
+// Problems (unknown names, roles) are returned, not fatal; the auto theme is used instead
+func loadTheme(config Config) (theme, []string) {
+	var problems []string
+
+	name := config.Theme
+	if name == "" || name == "auto" {
+		name = autoThemeName()
+	}
+	if colors, ok := builtinThemes[name]; ok {
+		return newTheme(name, colors), nil
+	}
+
+	for _, user := range config.Themes {
+		if user.Name != name {
+			continue
+		}
+		base := user.Base
+		if base == "" {
+			base = "dark"
+		}
+		colors, ok := builtinThemes[base]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("themes: %s: unknown base theme %q", user.Name, base))
+			colors = builtinThemes["dark"]
+		}
+
+		// Sorted so problems are reported in a stable order
+		roles := make([]string, 0, len(user.Colors))
+		for role := range user.Colors {
+			roles = append(roles, role)
+		}
+		sort.Strings(roles)
+		for _, role := range roles {
+			field, ok := themeRoles[role]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("themes: %s: unknown color %q", user.Name, role))
+				continue
+			}
+			*field(&colors) = lipgloss.Color(user.Colors[role])
+		}
+		return newTheme(name, colors), problems
+	}
+
+	problems = append(problems, fmt.Sprintf("theme: unknown theme %q", name))
+	auto := autoThemeName()
+	return newTheme(auto, builtinThemes[auto]), problems
+}
+
+// newTheme builds the styles of a theme from its colors
+func newTheme(name string, c themeColors) theme {
+	t := theme{
+		name:         name,
+		border:       lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(c.border),
+		activeBorder: lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(c.activeBorder),
+		cursor:       lipgloss.NewStyle().Bold(true).Foreground(c.cursor),
+		selected:     lipgloss.NewStyle().Foreground(c.selected),
+		category:     lipgloss.NewStyle().Foreground(c.category),
+		profile:      lipgloss.NewStyle().Foreground(c.profile),
+		running:      lipgloss.NewStyle().Foreground(c.running),
+		stopped:      lipgloss.NewStyle().Foreground(c.stopped),
+		crashed:      lipgloss.NewStyle().Foreground(c.crashed),
+		waiting:      lipgloss.NewStyle().Foreground(c.waiting),
+		git:          lipgloss.NewStyle().Foreground(c.git),
+		footerKey:    lipgloss.NewStyle().Foreground(c.footerKey),
+		footer:       lipgloss.NewStyle().Foreground(c.footer),
+		muted:        lipgloss.NewStyle().Foreground(c.muted),
+		heading:      lipgloss.NewStyle().Bold(true).Foreground(c.heading),
+		toast:        lipgloss.NewStyle().Foreground(c.success),
+		toastError:   lipgloss.NewStyle().Foreground(c.failure),
+		scrollbar:    lipgloss.NewStyle().Foreground(c.activeBorder),
+	}
+
+	// High contrast doesn't rely on color alone
+	if name == "high-contrast" {
+		t.cursor = t.cursor.Reverse(true)
+		t.selected = t.selected.Bold(true)
+		t.crashed = t.crashed.Bold(true)
+		t.toastError = t.toastError.Bold(true)
+		t.footerKey = t.footerKey.Bold(true)
+	}
+	return t
+}
+
+// paneBorder returns the border style of a pane, highlighted when it has focus
+func (t theme) paneBorder(focused bool) lipgloss.Style {
+	if focused {
+		return t.activeBorder
+	}
+	return t.border
+}
+
+// applyToHelp styles the footer's help model with the theme
+func (t theme) applyToHelp(h *help.Model) {
+	h.Styles.ShortKey = t.footerKey
+	h.Styles.ShortDesc = t.footer
+	h.Styles.ShortSeparator = t.muted
+	h.Styles.Ellipsis = t.muted
+	h.Styles.FullKey = t.footerKey
+	h.Styles.FullDesc = t.footer
+	h.Styles.FullSeparator = t.muted
+}
@@ -22,22 +22,12 @@ func buildTreeFromConfig(config Config) ([]launchItem, []launchItem) {
 				Icon:     proj.Icon,
 				Cwd:      expandPath(proj.Path), // Store project directory for CD
 				Children: []launchItem{},
+				DefaultProfile: proj.DefaultProfile,
+				WorktreeDir:    expandPath(proj.WorktreeDir),
 			}
 
-			// Add commands
-			for _, cmd := range proj.Commands {
-				cmdItem := launchItem{
-					Name:     cmd.Name,
-					Path:     "projects/" + proj.Name + "/" + cmd.Name,
-					ItemType: typeCommand,
-					Icon:     cmd.Icon,
-					Command:  cmd.Command,
-					Cwd:      expandPath(cmd.Cwd),
-					SpawnStr: cmd.Spawn,
-					DefaultSpawn: parseSpawnMode(cmd.Spawn),
-				}
-				item.Children = append(item.Children, cmdItem)
-			}
+			// Add commands (and sub-categories of commands)
+			item.Children = append(item.Children, buildCommandItems(proj.Commands, item.Path, &proj, item.Cwd)...)
 
 			// Add profiles
 			for _, prof := range proj.Profiles {
@@ -50,6 +40,16 @@ func buildTreeFromConfig(config Config) ([]launchItem, []launchItem) {
 					LayoutStr: prof.Layout,
 					Layout:    parseLayoutMode(prof.Layout),
 					Panes:     prof.Panes,
+					SessionName: prof.Session,
+					OnExistsStr: prof.OnExists,
+					OnExists:    parseOnExists(prof.OnExists),
+					TargetStr:   prof.Target,
+					Target:      parseSpawnTarget(prof.Target),
+					RunAsStr:    prof.RunAs,
+					RunAs:       parseRunMode(prof.RunAs),
+					Before:      joinHooks(proj.Before, prof.Before),
+					After:       joinHooks(prof.After, proj.After),
+					ProjectPath: item.Cwd,
 				}
 				item.Children = append(item.Children, profItem)
 			}
@@ -66,27 +66,24 @@ func buildTreeFromConfig(config Config) ([]launchItem, []launchItem) {
 				Path:     "tools/" + cat.Category,
 				ItemType: typeCategory,
 				Icon:     cat.Icon,
-				Children: []launchItem{},
-			}
-
-			for _, cmd := range cat.Items {
-				cmdItem := launchItem{
-					Name:     cmd.Name,
-					Path:     "tools/" + cat.Category + "/" + cmd.Name,
-					ItemType: typeCommand,
-					Icon:     cmd.Icon,
-					Command:  cmd.Command,
-					Cwd:      expandPath(cmd.Cwd),
-					SpawnStr: cmd.Spawn,
-					DefaultSpawn: parseSpawnMode(cmd.Spawn),
-				}
-				item.Children = append(item.Children, cmdItem)
+				Children: buildCommandItems(cat.Items, "tools/"+cat.Category, nil, ""),
 			}
 
 			globalItems = append(globalItems, item)
 		}
 	}
 
+	// AI tools go to left pane (global), in one category
+	if len(config.AI) > 0 {
+		globalItems = append(globalItems, launchItem{
+			Name:     "AI",
+			Path:     "ai",
+			ItemType: typeCategory,
+			Icon:     emojiAI,
+			Children: aiItems(buildCommandItems(config.AI, "ai", nil, "")),
+		})
+	}
+
 	// Scripts go to left pane (global)
 	if len(config.Scripts) > 0 {
 		for _, cat := range config.Scripts {
@@ -95,21 +92,7 @@ func buildTreeFromConfig(config Config) ([]launchItem, []launchItem) {
 				Path:     "scripts/" + cat.Category,
 				ItemType: typeCategory,
 				Icon:     cat.Icon,
-				Children: []launchItem{},
-			}
-
-			for _, cmd := range cat.Items {
-				cmdItem := launchItem{
-					Name:     cmd.Name,
-					Path:     "scripts/" + cat.Category + "/" + cmd.Name,
-					ItemType: typeCommand,
-					Icon:     cmd.Icon,
-					Command:  cmd.Command,
-					Cwd:      expandPath(cmd.Cwd),
-					SpawnStr: cmd.Spawn,
-					DefaultSpawn: parseSpawnMode(cmd.Spawn),
-				}
-				item.Children = append(item.Children, cmdItem)
+				Children: buildCommandItems(cat.Items, "scripts/"+cat.Category, nil, ""),
 			}
 
 			globalItems = append(globalItems, item)
@@ -119,6 +102,132 @@ func buildTreeFromConfig(config Config) ([]launchItem, []launchItem) {
 	return globalItems, projectItems
 }
 
+// buildCommandItems converts command entries under parent, recursing into sub-categories
+// Paths are built from the full ancestry; proj is set for a project's commands,
+// which get the project's hooks and directory
+func buildCommandItems(entries []CommandConfig, parent string, proj *ProjectConfig, projectDir string) []launchItem {
+	items := []launchItem{}
+	for _, cmd := range entries {
+		if cmd.isCategory() {
+			name := cmd.Category
+			if name == "" {
+				name = cmd.Name
+			}
+			path := parent + "/" + name
+			items = append(items, launchItem{
+				Name:     name,
+				Path:     path,
+				ItemType: typeCategory,
+				Icon:     cmd.Icon,
+				Children: buildCommandItems(cmd.Items, path, proj, projectDir),
+			})
+			continue
+		}
+
+		cmdItem := launchItem{
+			Name:     cmd.Name,
+			Path:     parent + "/" + cmd.Name,
+			ItemType: typeCommand,
+			Icon:     cmd.Icon,
+			Command:  cmd.Command,
+			Cwd:      expandPath(cmd.Cwd),
+			SpawnStr: cmd.Spawn,
+			DefaultSpawn: parseSpawnMode(cmd.Spawn),
+			RunAsStr: cmd.RunAs,
+			RunAs:    parseRunMode(cmd.RunAs),
+			DependsOn: cmd.DependsOn,
+			ReadyWhen: cmd.ReadyWhen,
+			Description: cmd.Description,
+			InfoFile:    cmd.InfoFile,
+			Repo:        cmd.Repo,
+			Prompt:      cmd.Prompt,
+			PromptFlag:  cmd.PromptFlag,
+			Before:    cmd.Before,
+			After:     cmd.After,
+		}
+		if proj != nil {
+			cmdItem.Before = joinHooks(proj.Before, cmd.Before)
+			cmdItem.After = joinHooks(cmd.After, proj.After)
+			cmdItem.ProjectPath = projectDir
+		}
+		items = append(items, cmdItem)
+	}
+	return items
+}
+
+// isCategory reports whether an entry is a sub-category (category: or items:) rather than a command
+func (c CommandConfig) isCategory() bool {
+	return c.Category != "" || len(c.Items) > 0
+}
+
+// duplicatePaths reports items that share a path with an earlier item
+// Paths identify items for expansion, selection and status, so duplicates misbehave
+func duplicatePaths(items []launchItem) []string {
+	seen := make(map[string]bool)
+	var problems []string
+	var walk func(items []launchItem)
+	walk = func(items []launchItem) {
+		for _, item := range items {
+			if seen[item.Path] {
+				problems = append(problems, fmt.Sprintf("duplicate item %q: rename one of them", item.Path))
+			}
+			seen[item.Path] = true
+			walk(item.Children)
+		}
+	}
+	walk(items)
+	return problems
+}
+
+// invalidSessionNames reports profiles whose session: name tmux would reject
+func invalidSessionNames(items []launchItem) []string {
+	var problems []string
+	for _, item := range items {
+		if item.ItemType == typeProfile && item.SessionName != "" {
+			if err := checkSessionName(item.SessionName); err != nil {
+				problems = append(problems, fmt.Sprintf("profile %q: %v", item.Path, err))
+			}
+		}
+		problems = append(problems, invalidSessionNames(item.Children)...)
+	}
+	return problems
+}
+
+// Values accepted by on_exists, target and run_as; the parse functions below
+// fall back to the default for anything else, so unknownSettings reports it
+var (
+	onExistsValues = []string{"attach", "recreate", "new"}
+	targetValues   = []string{"auto", "current-window", "new-window", "new-session", "switch-session"}
+	runAsValues    = []string{"keys", "process"}
+)
+
+// unknownSettings reports on_exists, target and run_as values that aren't one of the above
+func unknownSettings(items []launchItem) []string {
+	var problems []string
+	for _, item := range items {
+		check := func(key, value string, known []string) {
+			if value == "" {
+				return
+			}
+			for _, k := range known {
+				if value == k {
+					return
+				}
+			}
+			problems = append(problems, fmt.Sprintf("%s %q: unknown %s %q (use %s)",
+				strings.ToLower(item.ItemType.String()), item.Path, key, value, strings.Join(known, ", ")))
+		}
+		check("on_exists", item.OnExistsStr, onExistsValues)
+		check("target", item.TargetStr, targetValues)
+		check("run_as", item.RunAsStr, runAsValues)
+		for _, pane := range item.Panes {
+			check("run_as", pane.RunAs, runAsValues)
+		}
+		problems = append(problems, unknownSettings(item.Children)...)
+	}
+	return problems
+}
+
 // flattenTree converts hierarchical items into a flat list for display
 func flattenTree(items []launchItem, expandedItems map[string]bool) []launchTreeItem {
 	var result []launchTreeItem
@@ -151,6 +260,57 @@ func flattenTreeRecursive(item launchItem, depth int, isLast bool, parentLasts [
 	}
 }
 
+// profileItems converts a profile's panes into launch items
+// Panes are tracked under their profile's path
+func profileItems(profile launchItem) []launchItem {
+	var items []launchItem
+	for i, pane := range profile.Panes {
+		runAs := profile.RunAsStr
+		if pane.RunAs != "" {
+			runAs = pane.RunAs
+		}
+		name := pane.Name
+		if name == "" {
+			name = fmt.Sprintf("%s-pane-%d", profile.Name, i)
+		}
+		items = append(items, launchItem{
+			Name:      name,
+			DependsOn: pane.DependsOn,
+			ReadyWhen: pane.ReadyWhen,
+			Command:   pane.Command,
+			Cwd:       expandPath(pane.Cwd),
+			RunAsStr:  runAs,
+			RunAs:     parseRunMode(runAs),
+			Path:      profile.Path,
+		})
+	}
+	return items
+}
+
+// profileOptions returns a profile's batch options
+// Profiles without their own target follow the launcher's choice (fallback)
+func profileOptions(profile launchItem, fallback spawnTarget) batchOptions {
+	opts := batchOptions{
+		sessionName: profile.SessionName,
+		onExists:    profile.OnExists,
+		target:      profile.Target,
+	}
+	if opts.target == targetAuto {
+		opts.target = fallback
+	}
+	return opts
+}
+
+// joinHooks concatenates hook lists without aliasing either one
+func joinHooks(first, second []string) []string {
+	if len(first) == 0 && len(second) == 0 {
+		return nil
+	}
+	hooks := make([]string, 0, len(first)+len(second))
+	hooks = append(hooks, first...)
+	return append(hooks, second...)
+}
+
 // parseSpawnMode converts spawn string to spawnMode
 func parseSpawnMode(spawn string) spawnMode {
 	switch spawn {
@@ -166,6 +326,8 @@ func parseSpawnMode(spawn string) spawnMode {
 		return spawnTmuxLayout
 	case "current-pane":
 		return spawnCurrentPane
+	case "beside-editor":
+		return spawnBesideEditor
 	default:
 		return spawnTmuxWindow
 	}
@@ -189,6 +351,48 @@ func parseLayoutMode(layout string) tmuxLayout {
 	}
 }
 
+// parseOnExists converts on_exists string to sessionExistsMode
+func parseOnExists(onExists string) sessionExistsMode {
+	switch onExists {
+	case "attach":
+		return sessionAttach
+	case "recreate":
+		return sessionRecreate
+	case "new":
+		return sessionNew
+	default:
+		return sessionAttach
+	}
+}
+
+// parseSpawnTarget converts target string to spawnTarget
+func parseSpawnTarget(target string) spawnTarget {
+	switch target {
+	case "current-window":
+		return targetCurrentWindow
+	case "new-window":
+		return targetNewWindow
+	case "new-session":
+		return targetNewSession
+	case "switch-session":
+		return targetSwitchSession
+	default:
+		return targetAuto
+	}
+}
+
+// parseRunMode converts run_as string to runMode
+func parseRunMode(runAs string) runMode {
+	switch runAs {
+	case "process":
+		return runProcess
+	case "keys":
+		return runKeys
+	default:
+		return runKeys
+	}
+}
+
 // expandPath expands ~ to home directory
 func expandPath(path string) string {
 	if path == "" {
@@ -205,7 +409,8 @@ func expandPath(path string) string {
 }
 
 // renderTreeItem renders a single tree item with proper indentation
-func renderTreeItem(ti launchTreeItem, cursor int, index int, selected bool, expanded bool) string {
+// status is the process badge from model.statusBadge ("" when nothing was launched)
+func renderTreeItem(ti launchTreeItem, cursor int, index int, selected bool, expanded bool, status string, icons iconSet) string {
 	var sb strings.Builder
 
 	// Cursor indicator
@@ -216,55 +421,72 @@ func renderTreeItem(ti launchTreeItem, cursor int, index int, selected bool, exp
 	}
 
 	// Tree lines
+	sb.WriteString(treeIndent(ti, icons))
+
+	// Selection checkbox, or expansion indicator for categories
+	if marker := treeMarker(ti, selected, expanded, icons); marker != "" {
+		sb.WriteString(marker + " ")
+	}
+
+	// Icon
+	if icon := icons.item(ti.item.Icon); icon != "" {
+		sb.WriteString(icon + " ")
+	}
+
+	// Name
+	sb.WriteString(ti.item.Name)
+
+	// Type indicator (only show for profiles)
+	switch ti.item.ItemType {
+	case typeProfile:
+		sb.WriteString(fmt.Sprintf(" [%s]", ti.item.LayoutStr))
+	}
+
+	// Process status (running/exited/crashed)
+	if status != "" {
+		sb.WriteString(" " + status)
+	}
+
+	return sb.String()
+}
+
+// treeIndent renders the tree lines in front of an item
+func treeIndent(ti launchTreeItem, icons iconSet) string {
+	var sb strings.Builder
+	// Top-level items have no branch, so their column is skipped
 	for i, isLast := range ti.parentLasts {
-		if i == len(ti.parentLasts)-1 {
+		if i == 0 {
 			continue
 		}
 		if isLast {
 			sb.WriteString("  ")
 		} else {
-			sb.WriteString("│ ")
+			sb.WriteString(icons.treePipe + " ")
 		}
 	}
 
 	// Branch character
 	if ti.depth > 0 {
 		if ti.isLast {
-			sb.WriteString("└─")
+			sb.WriteString(icons.treeLast)
 		} else {
-			sb.WriteString("├─")
+			sb.WriteString(icons.treeBranch)
 		}
 	}
+	return sb.String()
+}
 
-	// Selection checkbox
-	if selected {
-		sb.WriteString(emojiSelected + " ")
-	} else if ti.item.ItemType == typeCommand || ti.item.ItemType == typeProfile {
-		sb.WriteString(emojiUnselected + " ")
-	}
-
-	// Expansion indicator for categories
-	if ti.item.ItemType == typeCategory {
-		if expanded {
-			sb.WriteString(emojiExpanded + " ")
-		} else {
-			sb.WriteString(emojiCollapsed + " ")
-		}
-	}
-
-	// Icon
-	if ti.item.Icon != "" {
-		sb.WriteString(ti.item.Icon + " ")
-	}
-
-	// Name
-	sb.WriteString(ti.item.Name)
-
-	// Type indicator (only show for profiles)
-	switch ti.item.ItemType {
-	case typeProfile:
-		sb.WriteString(fmt.Sprintf(" [%s]", ti.item.LayoutStr))
+// treeMarker is the checkbox of a command or profile, or the expansion glyph of a category
+func treeMarker(ti launchTreeItem, selected bool, expanded bool, icons iconSet) string {
+	switch {
+	case selected:
+		return icons.selected
+	case ti.item.ItemType == typeCommand || ti.item.ItemType == typeProfile:
+		return icons.unselected
+	case ti.item.ItemType == typeCategory && expanded:
+		return icons.expanded
+	case ti.item.ItemType == typeCategory:
+		return icons.collapsed
 	}
-
-	return sb.String()
+	return ""
 }
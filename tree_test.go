@@ -0,0 +1,78 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfigProblems(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   []string
+	}{
+		{"empty config", Config{}, nil},
+		{
+			"known values",
+			Config{
+				Tools: []CategoryConfig{{Category: "T", Items: []CommandConfig{{Name: "htop", RunAs: "process"}}}},
+				Projects: []ProjectConfig{{Name: "app", Profiles: []ProfileConfig{{
+					Name: "dev", Session: "app-dev", OnExists: "recreate", Target: "auto", RunAs: "keys",
+					Panes: []paneConfig{{Command: "make", RunAs: "process"}},
+				}}}},
+			},
+			nil,
+		},
+		{
+			"typos in profile settings",
+			Config{Projects: []ProjectConfig{{Name: "app", Profiles: []ProfileConfig{{
+				Name: "dev", OnExists: "atach", Target: "new-windw", RunAs: "proc",
+				Panes: []paneConfig{{Command: "make", RunAs: "key"}},
+			}}}}},
+			[]string{
+				`profile "projects/app/dev": unknown on_exists "atach" (use attach, recreate, new)`,
+				`profile "projects/app/dev": unknown target "new-windw" (use auto, current-window, new-window, new-session, switch-session)`,
+				`profile "projects/app/dev": unknown run_as "proc" (use keys, process)`,
+				`profile "projects/app/dev": unknown run_as "key" (use keys, process)`,
+			},
+		},
+		{
+			"typo in a nested command",
+			Config{Tools: []CategoryConfig{{Category: "T", Items: []CommandConfig{
+				{Category: "Sub", Items: []CommandConfig{{Name: "htop", RunAs: "Process"}}},
+			}}}},
+			[]string{`command "tools/T/Sub/htop": unknown run_as "Process" (use keys, process)`},
+		},
+		{
+			"session names tmux rejects",
+			Config{Projects: []ProjectConfig{{Name: "app", Profiles: []ProfileConfig{
+				{Name: "ok", Session: "my app"},
+				{Name: "dot", Session: "app.dev"},
+				{Name: "colon", Session: "app:dev"},
+			}}}},
+			[]string{
+				`profile "projects/app/dot": session name "app.dev" can't contain "." or ":"`,
+				`profile "projects/app/colon": session name "app:dev" can't contain "." or ":"`,
+			},
+		},
+		{
+			"duplicate paths",
+			Config{Tools: []CategoryConfig{{Category: "T", Items: []CommandConfig{{Name: "htop"}, {Name: "htop"}}}}},
+			[]string{`duplicate item "tools/T/htop": rename one of them`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			global, projects := buildTreeFromConfig(tt.config)
+			all := append(append([]launchItem{}, global...), projects...)
+			var got []string
+			got = append(got, duplicatePaths(all)...)
+			got = append(got, invalidSessionNames(projects)...)
+			got = append(got, unknownSettings(all)...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("problems =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
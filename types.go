@@ -1,7 +1,16 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
 )
 
 // Version is the current version of tui-launcher
@@ -39,6 +48,7 @@ const (
 	spawnTmuxSplitV                   // Tmux split vertical
 	spawnTmuxLayout                   // Tmux with custom layout
 	spawnCurrentPane                  // Replace current pane
+	spawnBesideEditor                 // Split beside the tmux pane running an editor
 )
 
 func (s spawnMode) String() string {
@@ -55,6 +65,8 @@ func (s spawnMode) String() string {
 		return "Tmux Layout"
 	case spawnCurrentPane:
 		return "Current Pane"
+	case spawnBesideEditor:
+		return "Beside Editor"
 	default:
 		return "Unknown"
 	}
@@ -88,6 +100,153 @@ func (l tmuxLayout) String() string {
 	}
 }
 
+// sessionExistsMode controls what happens when a profile's named session already exists
+type sessionExistsMode int
+
+const (
+	sessionAttach   sessionExistsMode = iota // Attach/switch to the existing session
+	sessionRecreate                          // Kill the existing session and build it again
+	sessionNew                               // Create another session with a numbered suffix
+)
+
+func (s sessionExistsMode) String() string {
+	switch s {
+	case sessionAttach:
+		return "attach"
+	case sessionRecreate:
+		return "recreate"
+	case sessionNew:
+		return "new"
+	default:
+		return "attach"
+	}
+}
+
+// spawnTarget represents where a batch or profile launch puts its panes
+type spawnTarget int
+
+const (
+	targetAuto          spawnTarget = iota // Current window inside tmux, new session outside
+	targetCurrentWindow                    // Split the window the launcher runs in
+	targetNewWindow                        // New window in the current session
+	targetNewSession                       // New detached session (stay where we are)
+	targetSwitchSession                    // New session, then switch-client/attach to it
+)
+
+func (t spawnTarget) String() string {
+	switch t {
+	case targetAuto:
+		return "auto"
+	case targetCurrentWindow:
+		return "current-window"
+	case targetNewWindow:
+		return "new-window"
+	case targetNewSession:
+		return "new-session"
+	case targetSwitchSession:
+		return "switch-session"
+	default:
+		return "auto"
+	}
+}
+
+// runMode controls how a command is started inside its tmux pane
+type runMode int
+
+const (
+	runKeys    runMode = iota // Type the command into the pane's shell (send-keys)
+	runProcess                // Run the command as the pane's process, kept with remain-on-exit
+)
+
+func (r runMode) String() string {
+	switch r {
+	case runKeys:
+		return "keys"
+	case runProcess:
+		return "process"
+	default:
+		return "keys"
+	}
+}
+
+// processState represents the lifecycle of something a launch started
+type processState int
+
+const (
+	stateRunning processState = iota // Pane/process still alive
+	stateExited                      // Finished with status 0 (or its pane was closed)
+	stateCrashed                     // Finished with a non-zero status or a signal
+	stateWaiting                     // Pane created, waiting for depends_on to become ready
+	stateStarting                    // Started, ready_when not satisfied yet
+	stateFailed                      // Never became ready (timeout or failed dependency)
+)
+
+func (p processState) String() string {
+	switch p {
+	case stateRunning:
+		return "running"
+	case stateExited:
+		return "exited"
+	case stateCrashed:
+		return "crashed"
+	case stateWaiting:
+		return "waiting"
+	case stateStarting:
+		return "starting"
+	case stateFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// launchPhase is the progress of one item in a launch
+type launchPhase int
+
+const (
+	phasePending  launchPhase = iota // Waiting for hooks or dependencies
+	phaseSpawning                    // Being created in tmux/xterm
+	phaseDone                        // Started successfully
+	phaseFailed                      // Could not be started
+)
+
+func (l launchPhase) String() string {
+	switch l {
+	case phasePending:
+		return "pending"
+	case phaseSpawning:
+		return "spawning"
+	case phaseDone:
+		return "done"
+	case phaseFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// dialogSection is the part of the spawn dialog that has focus
+type dialogSection int
+
+const (
+	sectionLayouts dialogSection = iota // Layout list with preview
+	sectionPanes                        // Item order (pane 1, 2, ...)
+	sectionTarget                       // Spawn target
+)
+
+func (d dialogSection) String() string {
+	switch d {
+	case sectionLayouts:
+		return "Layout"
+	case sectionPanes:
+		return "Panes"
+	case sectionTarget:
+		return "Target"
+	default:
+		return "Unknown"
+	}
+}
+
 // terminalType represents different terminal emulators with varying emoji rendering
 type terminalType int
 
@@ -99,6 +258,7 @@ const (
 	terminalITerm2
 	terminalXterm
 	terminalTermux
+	terminalConsole // Linux console and other terminals without emoji (TERM=linux, vt100, dumb)
 )
 
 func (t terminalType) String() string {
@@ -115,11 +275,93 @@ func (t terminalType) String() string {
 		return "xterm"
 	case terminalTermux:
 		return "Termux"
+	case terminalConsole:
+		return "console"
 	default:
 		return "Unknown"
 	}
 }
 
+// clickPart is the part of a tree row under a mouse click
+type clickPart int
+
+const (
+	clickRow      clickPart = iota // Anywhere else on the row
+	clickToggle                    // The expand/collapse glyph of a category
+	clickCheckbox                  // The checkbox of a command or profile
+)
+
+func (c clickPart) String() string {
+	switch c {
+	case clickRow:
+		return "row"
+	case clickToggle:
+		return "toggle"
+	case clickCheckbox:
+		return "checkbox"
+	default:
+		return "unknown"
+	}
+}
+
+// iconStyle is the kind of glyphs the tree and badges are drawn with
+type iconStyle int
+
+const (
+	iconsEmoji    iconStyle = iota // Emoji and Unicode symbols (default)
+	iconsNerdFont                  // Nerd Font glyphs, always one cell wide
+	iconsASCII                     // Plain ASCII, for terminals that get emoji widths wrong
+)
+
+func (i iconStyle) String() string {
+	switch i {
+	case iconsEmoji:
+		return "emoji"
+	case iconsNerdFont:
+		return "nerd-font"
+	case iconsASCII:
+		return "ascii"
+	default:
+		return "unknown"
+	}
+}
+
+// iconSet is the glyphs of one iconStyle
+type iconSet struct {
+	style      iconStyle
+	expanded   string
+	collapsed  string
+	selected   string
+	unselected string
+	running    string
+	stopped    string
+	crashed    string
+	waiting    string
+
+	// Tree lines
+	treeBranch string // ├─
+	treeLast   string // └─
+	treePipe   string // │
+
+	// Git badge
+	branch string
+	ahead  string
+	behind string
+	dirty  string
+	stash  string
+	clean  string
+
+	// Tree scrollbar
+	scrollTrack string
+	scrollThumb string
+
+	border lipgloss.Border
+
+	// items maps the emoji used as item icons to this set's glyph
+	// Icons missing from the map are shown as-is, or dropped when dropItems is set
+	items     map[string]string
+	dropItems bool
+}
 // paneType represents which pane has focus in multi-pane layouts
 type paneType int
 
@@ -193,12 +435,30 @@ const (
 	emojiUnselected   = "☐"  // U+2610
 	emojiRunning      = "🟢" // U+1F7E2
 	emojiStopped      = "🔴" // U+1F534
+	emojiCrashed      = "💥" // U+1F4A5
+	emojiWaiting      = "⏳" // U+23F3
+	emojiWorktree     = "🌿" // U+1F33F
 )
 
 // paneConfig represents a single pane in a profile
 type paneConfig struct {
-	Command string `yaml:"command"`
-	Cwd     string `yaml:"cwd"`
+	Name      string       `yaml:"name,omitempty"` // Referenced by other panes' depends_on
+	Command   string       `yaml:"command,omitempty"`
+	Cwd       string       `yaml:"cwd,omitempty"`
+	RunAs     string       `yaml:"run_as,omitempty"`     // process | keys (overrides the profile's run_as)
+	DependsOn []string     `yaml:"depends_on,omitempty"` // Names of panes that must be ready first
+	ReadyWhen *readyConfig `yaml:"ready_when,omitempty"` // How to tell this pane is ready
+}
+
+// readyConfig describes when a launched pane counts as ready
+// Every condition that is set must hold
+type readyConfig struct {
+	Port    int    `yaml:"port"`    // TCP port accepts connections
+	Host    string `yaml:"host"`    // Host for port (default localhost)
+	File    string `yaml:"file"`    // File exists
+	Log     string `yaml:"log"`     // Regex matched against pane output (capture-pane)
+	Command string `yaml:"command"` // Shell command exits 0
+	Timeout string `yaml:"timeout"` // Give up after this long (default 60s)
 }
 
 // paneInfo represents metadata for displaying item information
@@ -209,6 +469,39 @@ type paneInfo struct {
 	mdPath      string // Path to .md file for detailed info
 }
 
+// itemDoc is documentation loaded for an item: its info_file, man page or --help output
+type itemDoc struct {
+	source   string // "info_file", "man", "--help", or "" when nothing was found
+	text     string
+	markdown bool // Render as markdown (info_file) rather than preformatted text
+	loading  bool
+	helpBin  string // Program without a man page whose --help can be loaded on request
+}
+
+// gitStatus is a snapshot of a project's repository
+type gitStatus struct {
+	isRepo     bool
+	branch     string // "" when detached
+	commit     string // Short hash of HEAD
+	upstream   string // "" without a tracking branch
+	ahead      int
+	behind     int
+	dirty      int // Changed + untracked files
+	stashes    int
+	lastCommit string // Subject of HEAD
+	lastWhen   string // Relative date of HEAD ("2 hours ago")
+	worktrees  []gitWorktree // All worktrees of the repository, main first
+	err        error  // git failed for a reason other than "not a repo"
+}
+
+// gitWorktree is one entry of "git worktree list --porcelain"
+type gitWorktree struct {
+	path   string
+	branch string // "" when detached
+	head   string // Short hash of the checked out commit
+	bare   bool
+}
+
 // launchItem represents a command, category, or profile in the launcher
 type launchItem struct {
 	Name         string        `yaml:"name"`
@@ -219,13 +512,71 @@ type launchItem struct {
 	Cwd          string        `yaml:"cwd"`
 	DefaultSpawn spawnMode     `yaml:"-"` // Parsed from spawn string
 	SpawnStr     string        `yaml:"spawn"` // String from config
+	RunAs        runMode       `yaml:"-"` // Parsed from run_as string
+	RunAsStr     string        `yaml:"run_as"` // String from config
+	DependsOn    []string      `yaml:"depends_on"` // Names of batch items to wait for
+	ReadyWhen    *readyConfig  `yaml:"ready_when"` // Readiness check for dependents
+	Before       []string      `yaml:"before"` // Hooks before launch (project hooks first)
+	After        []string      `yaml:"after"`  // Hooks after launch (project hooks last)
+	ProjectPath  string        `yaml:"-"` // Owning project's directory ("" outside projects)
+	Description  string        `yaml:"description"`
+	InfoFile     string        `yaml:"info_file"` // Markdown docs for the info pane
+	Repo         string        `yaml:"repo"`
 	Children     []launchItem  `yaml:"items"`
 
+	// For AI tools
+	IsAI         bool          `yaml:"-"` // Starts in the focused project unless it has a cwd
+	Prompt       string        `yaml:"prompt"` // Passed to the tool; "ask" asks before each launch
+	PromptFlag   string        `yaml:"prompt_flag"` // Flag before the prompt ("" = positional argument)
+
+	// For projects and their worktrees
+	DefaultProfile string      `yaml:"default_profile"` // Profile launched in new worktrees
+	WorktreeDir    string      `yaml:"worktree_dir"` // Where new worktrees are created
+	IsWorktree     bool        `yaml:"-"` // A linked worktree of the parent project
+	Branch         string      `yaml:"-"` // Worktree's branch ("" when detached)
+
 	// For profiles
 	IsProfile    bool          `yaml:"-"`
 	Layout       tmuxLayout    `yaml:"-"` // Parsed from layout string
 	LayoutStr    string        `yaml:"layout"` // String from config
 	Panes        []paneConfig  `yaml:"panes"`
+	SessionName  string        `yaml:"session"` // Named tmux session (attach-or-create)
+	OnExists     sessionExistsMode `yaml:"-"` // Parsed from on_exists string
+	OnExistsStr  string        `yaml:"on_exists"` // String from config
+	Target       spawnTarget   `yaml:"-"` // Parsed from target string
+	TargetStr    string        `yaml:"target"` // String from config
+}
+
+// trackedPane is one tmux pane or xterm process created by a launch
+type trackedPane struct {
+	paneID   string      // tmux #{pane_id} ("" for xterm)
+	proc     *os.Process // xterm process (nil for tmux)
+	item     launchItem  // What runs there, used for restart
+	state    processState
+	exitCode int    // -1 when unknown (pane closed, killed by signal)
+	detail   string // Staging detail while waiting/starting/failed
+}
+
+// launchEntry is one item's row in the launch progress view
+type launchEntry struct {
+	name   string
+	paneID string // Set once the item has a pane (matches stageMsg updates)
+	phase  launchPhase
+	detail string
+}
+
+// launchProgress tracks the most recent launch for the progress view
+type launchProgress struct {
+	id      int
+	title   string
+	entries []launchEntry
+	visible bool
+}
+
+// errorLogEntry is one failure kept in the scrollable error log
+type errorLogEntry struct {
+	at   time.Time
+	text string
 }
 
 // launchTreeItem represents an item in the flattened tree view
@@ -257,12 +608,19 @@ type model struct {
 	projectTreeItems  []launchTreeItem   // Flattened tree for project pane
 	globalCursor      int                // Cursor position in global pane
 	projectCursor     int                // Cursor position in project pane
+	globalOffset      int                // First visible row of the global tree
+	projectOffset     int                // First visible row of the project tree
+	lastClickPath     string             // Item of the last click on a row, for double-clicks
+	lastClickAt       time.Time
 	globalExpanded    map[string]bool    // Expanded items in global pane
 	projectExpanded   map[string]bool    // Expanded items in project pane
 
 	// Info pane state
 	currentInfo       paneInfo           // Info for selected item
 	infoContent       string             // Rendered content for info pane
+	infoPath          string             // Item the info pane shows (resets scrolling on change)
+	infoOffset        int                // Scroll position in the info pane
+	docs              map[string]itemDoc // Loaded docs, keyed by item path
 	showingInfo       bool               // Toggle for mobile mode
 	showingProjects   bool               // Toggle for compact mode (global vs projects)
 
@@ -273,12 +631,66 @@ type model struct {
 	showSpawnDialog bool
 	selectedLayout  tmuxLayout
 	layoutCursor    int // For layout picker in dialog
+	dialogItems     []launchItem  // Items in pane order while the dialog is open
+	dialogCursor    int           // Highlighted item in the pane order list
+	dialogSection   dialogSection // Focused part of the dialog
+	dialogTarget    spawnTarget   // Target picked in the dialog
+
+	// Help overlay and command palette
+	showHelp      bool
+	helpOffset    int // Scroll position in the help overlay
+	showPalette   bool
+	paletteQuery  string
+	paletteCursor int
+
+	// New worktree prompt
+	showWorktreePrompt bool
+	worktreeProject    launchItem // Project the worktree is created for
+	worktreeBranch     string     // Branch name being typed
+
+	// AI prompt (prompt: ask)
+	showAIPrompt bool
+	aiItem       launchItem // AI tool being launched, context already resolved
+	aiPrompt     string     // Prompt being typed
+
+	// Import prompt (tmuxinator/tmuxp files)
+	showImportPrompt bool
+	importPath       string // File or glob being typed
+
+	spawnTarget     spawnTarget // Target for batch launches (profiles may override)
+	dryRun          bool        // Enter shows the spawn plan instead of launching
+	popup           bool        // Running in a tmux popup: close after a successful launch
+	rpcLaunches     map[int]rpcPending // Launch requests by launch id, answered when they complete
+	serving         bool        // Headless control socket server: no terminal to attach sessions to
+	plan            *spawnPlan  // Last computed plan (exported with p)
+
+	// Launch feedback
+	launch         launchProgress  // Progress of the latest launch
+	launchSeq      int             // Last launch id handed out
+	toastText      string          // One-line notification (replaces footer)
+	toastIsError   bool
+	toastSeq       int             // Matches toastExpiredMsg to the current toast
+	errorLog       []errorLogEntry // Failed launches/hooks/tmux calls, oldest first
+	showErrorLog   bool            // Error log overlay is open
+	errorLogOffset int             // Scroll position in the error log
+
+	// Git status of projects (keyed by launchItem.Path)
+	gitStatus     map[string]gitStatus
+	gitRefreshing bool // A refresh is running (refreshes never overlap)
+
+	// Process tracking (keyed by launchItem.Path)
+	tracked       map[string][]trackedPane
+	statusPolling bool // A status poll loop is scheduled
 
 	// Config
 	config        Config
 
 	// UI state
 	spinner       spinner.Model
+	keys          keyMap     // Active key bindings (defaults + config)
+	theme         theme      // Active theme
+	icons         iconSet    // Active icon set (emoji, Nerd Font or ASCII)
+	help          help.Model // Renders the footer from keys
 	loading       bool
 	err           error
 
@@ -297,15 +709,159 @@ type Config struct {
 	Tools    []CategoryConfig `yaml:"tools"`
 	AI       []CommandConfig  `yaml:"ai"`
 	Scripts  []CategoryConfig `yaml:"scripts"`
+	Keys     map[string]keyList `yaml:"keys"` // Action name -> keys (empty disables the action)
+	Theme    string           `yaml:"theme"`  // auto (default), dark, light, high-contrast or a user theme
+	Themes   []ThemeConfig    `yaml:"themes"` // User themes
+	Icons    string           `yaml:"icons"`  // auto (default), emoji, nerd-font or ascii
+	Popup    PopupConfig      `yaml:"popup"`
+}
+
+// PopupConfig sizes the tmux popup of "tui-launcher popup"
+type PopupConfig struct {
+	Width  string `yaml:"width"`  // Columns or percentage (default: 90%)
+	Height string `yaml:"height"` // Lines or percentage (default: 90%)
+	Key    string `yaml:"key"`    // Key in the printed binding snippet (default: O)
+}
+
+// ThemeConfig is a user theme: a built-in theme with some colors replaced
+type ThemeConfig struct {
+	Name   string            `yaml:"name"`
+	Base   string            `yaml:"base"`   // Built-in theme to start from (default: dark)
+	Colors map[string]string `yaml:"colors"` // Role -> color ("#ff8800", "208" or "9")
+}
+
+// themeColors are the colors of a theme, one per role
+type themeColors struct {
+	border       lipgloss.TerminalColor
+	activeBorder lipgloss.TerminalColor
+	cursor       lipgloss.TerminalColor
+	selected     lipgloss.TerminalColor
+	category     lipgloss.TerminalColor
+	profile      lipgloss.TerminalColor
+	running      lipgloss.TerminalColor
+	stopped      lipgloss.TerminalColor
+	crashed      lipgloss.TerminalColor
+	waiting      lipgloss.TerminalColor
+	git          lipgloss.TerminalColor
+	footerKey    lipgloss.TerminalColor
+	footer       lipgloss.TerminalColor
+	muted        lipgloss.TerminalColor
+	heading      lipgloss.TerminalColor
+	success      lipgloss.TerminalColor
+	failure      lipgloss.TerminalColor
+}
+
+// theme is the set of styles the UI is drawn with
+type theme struct {
+	name         string
+	border       lipgloss.Style // Pane and dialog borders
+	activeBorder lipgloss.Style // Border of the focused pane
+	cursor       lipgloss.Style // Cursor line
+	selected     lipgloss.Style // Checked items
+	category     lipgloss.Style
+	profile      lipgloss.Style
+	running      lipgloss.Style // Status badges
+	stopped      lipgloss.Style
+	crashed      lipgloss.Style
+	waiting      lipgloss.Style
+	git          lipgloss.Style
+	footerKey    lipgloss.Style
+	footer       lipgloss.Style
+	muted        lipgloss.Style // Hints and separators
+	heading      lipgloss.Style
+	toast        lipgloss.Style
+	toastError   lipgloss.Style
+	scrollbar    lipgloss.Style // Scrollbar thumb of a tree pane
+}
+
+// keyList is the keys bound to an action: a single key or a list
+type keyList []string
+
+// UnmarshalYAML accepts both `quit: q` and `quit: [q, ctrl+q]`
+func (k *keyList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		if value.Value == "" {
+			*k = keyList{}
+		} else {
+			*k = keyList{value.Value}
+		}
+		return nil
+	}
+	var keys []string
+	if err := value.Decode(&keys); err != nil {
+		return err
+	}
+	*k = keys
+	return nil
+}
+
+// keyMap holds the binding of every action in the main view
+type keyMap struct {
+	Up             key.Binding
+	Down           key.Binding
+	Expand         key.Binding
+	Collapse       key.Binding
+	PageUp         key.Binding
+	PageDown       key.Binding
+	HalfPageUp     key.Binding
+	HalfPageDown   key.Binding
+	Top            key.Binding
+	Bottom         key.Binding
+	InfoUp         key.Binding
+	InfoDown       key.Binding
+	SwitchPane     key.Binding
+	ToggleInfo     key.Binding
+	Select         key.Binding
+	Launch         key.Binding
+	ClearSelection key.Binding
+	ToggleTmux     key.Binding
+	SpawnTarget    key.Binding
+	DryRun         key.Binding
+	ExportPlan     key.Binding
+	NewWorktree    key.Binding
+	MoveItemUp     key.Binding // Spawn dialog only
+	MoveItemDown   key.Binding // Spawn dialog only
+	Focus          key.Binding
+	Restart        key.Binding
+	Kill           key.Binding
+	ErrorLog       key.Binding
+	Dismiss        key.Binding
+	Help           key.Binding
+	HelpOutput     key.Binding
+	Palette        key.Binding
+	Reload         key.Binding
+	EditConfig     key.Binding
+	Import         key.Binding
+	Quit           key.Binding
+}
+
+// keyGroup is a titled group of bindings in help
+type keyGroup struct {
+	title    string
+	bindings []key.Binding
+}
+
+// paletteEntry is one result in the command palette: an action or an item
+type paletteEntry struct {
+	title   string
+	hint    string       // Key of an action, tree path of an item
+	action  string // Config name of an action ("" for items)
+	item    launchItem
+	project bool // Item lives in the projects pane
+	score   int  // Fuzzy match score (higher is better)
 }
 
 // ProjectConfig represents a project with commands and profiles
 type ProjectConfig struct {
 	Name     string          `yaml:"name"`
-	Icon     string          `yaml:"icon"`
-	Path     string          `yaml:"path"`
-	Commands []CommandConfig `yaml:"commands"`
-	Profiles []ProfileConfig `yaml:"profiles"`
+	Icon     string          `yaml:"icon,omitempty"`
+	Path     string          `yaml:"path,omitempty"`
+	Commands []CommandConfig `yaml:"commands,omitempty"`
+	Profiles []ProfileConfig `yaml:"profiles,omitempty"`
+	Before   []string        `yaml:"before,omitempty"` // Hooks before any launch in this project
+	After    []string        `yaml:"after,omitempty"`  // Hooks after any launch in this project
+	DefaultProfile string    `yaml:"default_profile,omitempty"` // Profile launched in new worktrees (default: first)
+	WorktreeDir    string    `yaml:"worktree_dir,omitempty"`    // Parent dir for new worktrees (default: beside the repo)
 }
 
 // CategoryConfig represents a category of commands
@@ -322,14 +878,50 @@ type CommandConfig struct {
 	Command string `yaml:"command"`
 	Cwd     string `yaml:"cwd"`
 	Spawn   string `yaml:"spawn"`
+	RunAs   string `yaml:"run_as"`
+
+	// Documentation shown in the info pane
+	Description string `yaml:"description"`
+	InfoFile    string `yaml:"info_file"` // Markdown file (~ and cwd-relative paths allowed)
+	Repo        string `yaml:"repo"`
+
+	// Staging within a batch launch
+	DependsOn []string     `yaml:"depends_on"`
+	ReadyWhen *readyConfig `yaml:"ready_when"`
+
+	// Hooks around the launch
+	Before []string `yaml:"before"`
+	After  []string `yaml:"after"`
+
+	// Sub-category: an entry with category: or items: holds more entries
+	Category string          `yaml:"category"`
+	Items    []CommandConfig `yaml:"items"`
+	Include  string          `yaml:"include"` // YAML file with a list of entries, relative to the including file
+
+	// AI tools (ai: section)
+	Prompt     string `yaml:"prompt"`      // Prompt passed on launch; "ask" asks for it each time
+	PromptFlag string `yaml:"prompt_flag"` // Flag the prompt follows, e.g. --message ("" = positional)
 }
 
 // ProfileConfig represents a multi-pane launch configuration
 type ProfileConfig struct {
-	Name   string       `yaml:"name"`
-	Icon   string       `yaml:"icon"`
-	Layout string       `yaml:"layout"`
-	Panes  []paneConfig `yaml:"panes"`
+	Name     string       `yaml:"name"`
+	Icon     string       `yaml:"icon,omitempty"`
+	Layout   string       `yaml:"layout,omitempty"`
+	Panes    []paneConfig `yaml:"panes,omitempty"`
+	Session  string       `yaml:"session,omitempty"`   // Optional fixed tmux session name
+	OnExists string       `yaml:"on_exists,omitempty"` // attach | recreate | new
+	Target   string       `yaml:"target,omitempty"`    // Default spawn target for this profile
+	RunAs    string       `yaml:"run_as,omitempty"`    // process | keys for all panes
+	Before   []string     `yaml:"before,omitempty"`    // Hooks before the launch
+	After    []string     `yaml:"after,omitempty"`     // Hooks after a successful launch
+}
+
+// batchOptions carries per-launch settings for spawner.multiple
+type batchOptions struct {
+	sessionName string            // Named session to attach-or-create ("" = legacy behavior)
+	onExists    sessionExistsMode // What to do when sessionName already exists
+	target      spawnTarget       // Where the panes go
 }
 
 // layoutOption represents a layout choice in the spawn dialog
@@ -342,7 +934,163 @@ type layoutOption struct {
 
 // spawnCompleteMsg is sent when spawning completes
 type spawnCompleteMsg struct {
-	err error
+	launchID  int // launchProgress.id this result belongs to (0 = untracked action)
+	err       error
+	paneIDs   []string     // tmux #{pane_id}s created by the launch (empty for xterm/direct)
+	paneItems []launchItem // Item running in each paneIDs entry
+	proc      *exec.Cmd    // Started xterm process (nil for tmux)
+	procItem  launchItem   // Item running in proc
+	stages    <-chan stageMsg // Progress of depends_on/ready_when staging (nil if none)
+	attach    string          // Session to attach the terminal to (outside tmux)
+	hookErr   error           // Failed after hook (the launch itself succeeded)
+}
+
+// attachDoneMsg reports the end of a tmux attach (the user detached)
+type attachDoneMsg struct {
+	session string
+	err     error
+}
+
+// stageMsg reports a staged pane changing state during a batch launch
+type stageMsg struct {
+	paneID string
+	state  processState
+	detail string          // e.g. "waiting for db", "not ready after 1m0s"
+	stages <-chan stageMsg // Channel to keep listening on
+}
+
+// directRunMsg is sent when before hooks passed for a direct-mode launch
+type directRunMsg struct {
+	item launchItem
+}
+
+// hookStep is one before/after hook command and where it runs
+type hookStep struct {
+	command string
+	dir     string
+}
+
+// hookError reports a failed before/after hook with its captured output
+type hookError struct {
+	phase   string // "before" or "after"
+	command string
+	output  string // Tail of combined stdout/stderr
+	err     error
+}
+
+func (e *hookError) Error() string {
+	msg := fmt.Sprintf("%s hook %q failed: %v", e.phase, e.command, e.err)
+	if e.output != "" {
+		msg += "\n\n" + e.output
+	}
+	return msg
+}
+
+func (e *hookError) Unwrap() error {
+	return e.err
+}
+
+// tmuxError is a failed tmux invocation with its stderr
+type tmuxError struct {
+	args   []string
+	stderr string
+	err    error
+}
+
+func (e *tmuxError) Error() string {
+	msg := "tmux " + e.args[0] + ": "
+	if e.stderr != "" {
+		return msg + e.stderr
+	}
+	return msg + e.err.Error()
+}
+
+func (e *tmuxError) Unwrap() error {
+	return e.err
+}
+
+// spawner executes (or, in a dry run, records) the invocations of a launch
+type spawner struct {
+	dryRun bool
+	steps  []planStep
+	nextID int // Counter for placeholder window/pane ids
+}
+
+// planStep is one recorded invocation of a dry run
+type planStep struct {
+	args       []string // Program and arguments ("" args = comment only)
+	dir        string   // Working directory to run in ("" = unchanged)
+	assign     []string // Variables bound to the command's output (placeholder ids)
+	shell      string   // Raw shell line instead of args (readiness waits)
+	note       string   // Comment explaining the next steps
+	background bool     // Started without waiting (xterm)
+}
+
+// spawnPlan is what a launch would run, computed without running anything
+type spawnPlan struct {
+	title string
+	steps []planStep
+	err   error // Where a real launch would stop (e.g. invalid target)
+}
+
+// docLoadedMsg delivers an item's documentation
+type docLoadedMsg struct {
+	path string
+	doc  itemDoc
+}
+
+// gitStatusMsg delivers one project's git status during a refresh
+type gitStatusMsg struct {
+	path    string
+	status  gitStatus
+	updates <-chan gitStatusMsg // Remaining results of this refresh
+}
+
+// gitRefreshDoneMsg ends a git status refresh
+type gitRefreshDoneMsg struct{}
+
+// worktreeCreatedMsg reports the result of "git worktree add"
+type worktreeCreatedMsg struct {
+	project   launchItem
+	worktree  gitWorktree
+	worktrees []gitWorktree // The repository's worktrees after the add
+	err       error
+}
+
+// importDoneMsg reports tmuxinator/tmuxp files imported into the config
+type importDoneMsg struct {
+	projects []ProjectConfig
+	warnings []string // Things in the files that couldn't be imported
+	err      error
+}
+
+// gitTickMsg schedules the next git status refresh
+type gitTickMsg struct{}
+
+// planMsg carries a computed dry-run plan
+type planMsg struct {
+	plan *spawnPlan
+}
+
+// toastExpiredMsg hides a toast after its display time
+type toastExpiredMsg struct {
+	id int
+}
+
+// statusTickMsg is sent periodically to poll launched panes
+type statusTickMsg struct{}
+
+// paneStatusMsg carries the state of every tmux pane, keyed by #{pane_id}
+type paneStatusMsg struct {
+	panes    map[string]trackedPane
+	err      error
+	fromTick bool // Part of the statusTick loop (vs. a one-off poll after kill)
+}
+
+// processExitedMsg is sent when a tracked xterm process exits
+type processExitedMsg struct {
+	pid      int
+	exitCode int
 }
 
 // configLoadedMsg is sent when config loads
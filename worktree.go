@@ -0,0 +1,457 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// worktree.go - Git worktrees as child nodes of projects
+// Each linked worktree gets a copy of its project's commands and profiles,
+// with working directories rebased from the project onto the worktree
+
+// readWorktrees lists the worktrees of the repository at dir
+func readWorktrees(dir string) ([]gitWorktree, error) {
+	output, err := runGit(dir, "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	return parseWorktrees(output), nil
+}
+
+// parseWorktrees parses "git worktree list --porcelain"
+// Worktrees whose directory is gone (prunable) are left out
+func parseWorktrees(output string) []gitWorktree {
+	var worktrees []gitWorktree
+	var current *gitWorktree
+	prunable := false
+
+	flush := func() {
+		if current != nil && !prunable {
+			worktrees = append(worktrees, *current)
+		}
+		current, prunable = nil, false
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "worktree":
+			flush()
+			current = &gitWorktree{path: value}
+		case "HEAD":
+			if current != nil && len(value) >= 7 {
+				current.head = value[:7]
+			}
+		case "branch":
+			if current != nil {
+				current.branch = strings.TrimPrefix(value, "refs/heads/")
+			}
+		case "bare":
+			if current != nil {
+				current.bare = true
+			}
+		case "prunable":
+			prunable = true
+		}
+	}
+	flush()
+	return worktrees
+}
+
+// worktreeRoot finds the worktree that contains dir
+func worktreeRoot(worktrees []gitWorktree, dir string) (gitWorktree, bool) {
+	dir = filepath.Clean(dir)
+	var root gitWorktree
+	found := false
+	for _, wt := range worktrees {
+		path := filepath.Clean(wt.path)
+		if (dir == path || strings.HasPrefix(dir, path+string(filepath.Separator))) && len(path) > len(root.path) {
+			root, found = wt, true
+		}
+	}
+	return root, found
+}
+
+// sameWorktrees reports whether two worktree lists are identical
+func sameWorktrees(a, b []gitWorktree) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// withWorktrees replaces each project's worktree children with its current worktrees
+func withWorktrees(projects []launchItem, statuses map[string]gitStatus) []launchItem {
+	result := make([]launchItem, len(projects))
+	for i, project := range projects {
+		var children []launchItem
+		for _, child := range project.Children {
+			if !child.IsWorktree {
+				children = append(children, child)
+			}
+		}
+
+		if status := statuses[project.Path]; project.Cwd != "" && len(status.worktrees) > 1 {
+			if root, ok := worktreeRoot(status.worktrees, project.Cwd); ok {
+				seen := make(map[string]bool)
+				for _, wt := range status.worktrees {
+					if wt.bare || wt.path == root.path {
+						continue
+					}
+					node := worktreeNode(project, root, wt)
+					base := node.Path
+					for n := 2; seen[node.Path]; n++ {
+						node.Path = fmt.Sprintf("%s-%d", base, n)
+					}
+					seen[node.Path] = true
+					children = append(children, rebaseChildren(node, project, root.path, wt.path))
+				}
+			}
+		}
+
+		project.Children = children
+		result[i] = project
+	}
+	return result
+}
+
+// worktreeNode creates the tree node for one of a project's worktrees
+// root is the worktree the project itself lives in (the project may be a subdirectory)
+func worktreeNode(project launchItem, root, wt gitWorktree) launchItem {
+	dir := wt.path
+	if rel, err := filepath.Rel(root.path, project.Cwd); err == nil && rel != "." {
+		dir = filepath.Join(wt.path, rel)
+	}
+
+	name := wt.branch
+	if name == "" {
+		name = "(detached " + wt.head + ")"
+	}
+
+	return launchItem{
+		Name:       name,
+		Path:       project.Path + "/worktrees/" + filepath.Base(wt.path),
+		ItemType:   typeCategory,
+		Icon:       emojiWorktree,
+		Cwd:        dir,
+		IsWorktree: true,
+		Branch:     wt.branch,
+	}
+}
+
+// rebaseChildren gives a worktree node copies of the project's commands and profiles
+// that run in the worktree (at to) instead of the project's worktree (at from)
+func rebaseChildren(node, project launchItem, from, to string) launchItem {
+	// Without a cwd, items run in the worktree's copy of the project directory
+	rebase := func(dir string) string {
+		if dir == "" {
+			return node.Cwd
+		}
+		return rebaseDir(dir, from, to)
+	}
+
+	// Sub-categories are copied with their paths moved under the node
+	var copyItems func(items []launchItem, parent string) []launchItem
+	copyItems = func(items []launchItem, parent string) []launchItem {
+		var result []launchItem
+		for _, child := range items {
+			if child.IsWorktree {
+				continue
+			}
+			child.Path = parent + "/" + child.Name
+			if child.ItemType == typeCategory {
+				child.Children = copyItems(child.Children, child.Path)
+				result = append(result, child)
+				continue
+			}
+			child.Cwd = rebase(child.Cwd)
+			child.ProjectPath = node.Cwd
+
+			if len(child.Panes) > 0 {
+				panes := make([]paneConfig, len(child.Panes))
+				for i, pane := range child.Panes {
+					pane.Cwd = rebase(expandPath(pane.Cwd))
+					panes[i] = pane
+				}
+				child.Panes = panes
+			}
+
+			// Keep named sessions of different worktrees apart
+			if child.SessionName != "" {
+				child.SessionName += "-" + strings.NewReplacer("/", "-", ".", "-", ":", "-").Replace(node.Name)
+			}
+
+			result = append(result, child)
+		}
+		return result
+	}
+
+	node.Children = copyItems(project.Children, node.Path)
+	return node
+}
+
+// rebaseDir moves dir from inside one worktree to the same place inside another
+// Directories outside the worktree are kept
+func rebaseDir(dir, from, to string) string {
+	rel, err := filepath.Rel(from, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return dir
+	}
+	return filepath.Join(to, rel)
+}
+
+// applyWorktrees rebuilds the project tree with the latest worktrees,
+// keeping the cursor on the same item
+func (m *model) applyWorktrees() {
+	current := ""
+	if m.projectCursor < len(m.projectTreeItems) {
+		current = m.projectTreeItems[m.projectCursor].item.Path
+	}
+
+	m.projectItems = withWorktrees(m.projectItems, m.gitStatus)
+	m.projectTreeItems = flattenTree(m.projectItems, m.projectExpanded)
+	m.rootItems = append(append([]launchItem{}, m.globalItems...), m.projectItems...)
+	m.treeItems = flattenTree(m.rootItems, m.expandedItems)
+
+	m.selectProjectItem(current)
+	m.updateInfoPane()
+}
+
+// selectProjectItem moves the project cursor to the item with path (if shown)
+func (m *model) selectProjectItem(path string) {
+	for i, ti := range m.projectTreeItems {
+		if ti.item.Path == path {
+			m.projectCursor = i
+			return
+		}
+	}
+	if m.projectCursor >= len(m.projectTreeItems) {
+		m.projectCursor = max(len(m.projectTreeItems)-1, 0)
+	}
+}
+
+// currentProject returns the project the current item belongs to
+func (m model) currentProject() (launchItem, bool) {
+	item, ok := m.currentItem()
+	if !ok {
+		return launchItem{}, false
+	}
+	for _, project := range m.projectItems {
+		if item.Path == project.Path || strings.HasPrefix(item.Path, project.Path+"/") {
+			return project, project.Cwd != ""
+		}
+	}
+	return launchItem{}, false
+}
+
+// defaultProfile returns the profile launched in a new worktree:
+// the project's default_profile, or its first profile
+func defaultProfile(node launchItem, name string) (launchItem, bool) {
+	var first *launchItem
+	for i, child := range node.Children {
+		if child.ItemType != typeProfile {
+			continue
+		}
+		if child.Name == name {
+			return child, true
+		}
+		if first == nil {
+			first = &node.Children[i]
+		}
+	}
+	if first == nil || name != "" {
+		return launchItem{}, false
+	}
+	return *first, true
+}
+
+// newWorktreeDir picks the directory for a branch's new worktree:
+// <worktree_dir>/<branch>, or <repo>-<branch> beside the repository
+func newWorktreeDir(project launchItem, status gitStatus, branch string) string {
+	suffix := strings.ReplaceAll(branch, "/", "-")
+	if project.WorktreeDir != "" {
+		dir := project.WorktreeDir
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(project.Cwd, dir)
+		}
+		return filepath.Join(dir, suffix)
+	}
+
+	repo := project.Cwd
+	if root, ok := worktreeRoot(status.worktrees, project.Cwd); ok {
+		repo = root.path
+	}
+	return filepath.Join(filepath.Dir(repo), filepath.Base(repo)+"-"+suffix)
+}
+
+// checkBranchName rejects what git wouldn't take as a new branch name
+// A leading "-" would otherwise be read as an option
+func checkBranchName(projectDir, branch string) error {
+	if strings.HasPrefix(branch, "-") {
+		return fmt.Errorf("invalid branch name %q: can't start with \"-\"", branch)
+	}
+	if _, err := runGit(projectDir, "check-ref-format", "--branch", branch); err != nil {
+		return fmt.Errorf("invalid branch name %q", branch)
+	}
+	return nil
+}
+
+// worktreeAddArgs is the git command creating a worktree for branch at path
+// Existing branches (local, or a remote's for git to track) are checked out; new ones are created
+func worktreeAddArgs(projectDir, branch, path string) []string {
+	args := []string{"git", "-C", projectDir, "worktree", "add"}
+	_, local := runGit(projectDir, "rev-parse", "--verify", "--quiet", "--end-of-options", "refs/heads/"+branch)
+	remote, _ := runGit(projectDir, "for-each-ref", "--format=%(refname)", "--", "refs/remotes/*/"+branch)
+	if local != nil && remote == "" {
+		return append(args, "-b", branch, "--", path)
+	}
+	return append(args, "--", path, branch)
+}
+
+// createWorktree runs "git worktree add" in the background
+func createWorktree(project launchItem, branch, path string) tea.Cmd {
+	return func() tea.Msg {
+		if err := checkBranchName(project.Cwd, branch); err != nil {
+			return worktreeCreatedMsg{project: project, err: err}
+		}
+		args := worktreeAddArgs(project.Cwd, branch, path)
+		if _, err := runGit(project.Cwd, args[3:]...); err != nil {
+			return worktreeCreatedMsg{project: project, err: err}
+		}
+		worktrees, err := readWorktrees(project.Cwd)
+		if err != nil {
+			return worktreeCreatedMsg{project: project, err: err}
+		}
+		for _, wt := range worktrees {
+			if filepath.Clean(wt.path) == filepath.Clean(path) {
+				return worktreeCreatedMsg{project: project, worktree: wt, worktrees: worktrees}
+			}
+		}
+		return worktreeCreatedMsg{project: project, err: fmt.Errorf("worktree %s was not created", path)}
+	}
+}
+
+// openWorktreePrompt asks for the branch of a new worktree of project
+func (m *model) openWorktreePrompt(project launchItem) {
+	m.showWorktreePrompt = true
+	m.worktreeProject = project
+	m.worktreeBranch = ""
+}
+
+// updateWorktreePrompt handles keys while the new worktree prompt is open
+func (m model) updateWorktreePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var action promptAction
+	m.worktreeBranch, action = editPrompt(m.worktreeBranch, msg)
+	switch action {
+	case promptQuit:
+		return m, tea.Quit
+
+	case promptCancel:
+		m.showWorktreePrompt = false
+
+	case promptSubmit:
+		branch := strings.TrimSpace(m.worktreeBranch)
+		if branch == "" {
+			return m, nil
+		}
+		m.showWorktreePrompt = false
+
+		project := m.worktreeProject
+		path := newWorktreeDir(project, m.gitStatus[project.Path], branch)
+		if m.dryRun {
+			return m, planCmd(func() *spawnPlan { return m.planWorktree(project, branch, path) })
+		}
+		return m, tea.Batch(
+			createWorktree(project, branch, path),
+			m.showToast("Creating worktree "+path+"…", false),
+		)
+	}
+	return m, nil
+}
+
+// planWorktree computes the dry-run plan of creating a worktree and launching its default profile
+func (m model) planWorktree(project launchItem, branch, path string) *spawnPlan {
+	if err := checkBranchName(project.Cwd, branch); err != nil {
+		return &spawnPlan{title: "worktree " + branch, err: err}
+	}
+	add := planStep{args: worktreeAddArgs(project.Cwd, branch, path)}
+
+	root, _ := worktreeRoot(m.gitStatus[project.Path].worktrees, project.Cwd)
+	if root.path == "" {
+		root.path = project.Cwd
+	}
+	node := rebaseChildren(worktreeNode(project, root, gitWorktree{path: path, branch: branch}), project, root.path, path)
+	profile, ok := defaultProfile(node, project.DefaultProfile)
+	if !ok {
+		return &spawnPlan{title: "worktree " + branch, steps: []planStep{add}}
+	}
+
+	items, opts := profileItems(profile), profileOptions(profile, m.spawnTarget)
+	plan := planLaunch(profile.Name+" @ "+branch, []launchItem{profile}, func(s *spawner) spawnCompleteMsg {
+		return s.multiple(items, profile.Layout, opts)
+	})
+	plan.steps = append([]planStep{add}, plan.steps...)
+	return plan
+}
+
+// launchInWorktree shows a newly created worktree and launches its default profile
+func (m *model) launchInWorktree(msg worktreeCreatedMsg) tea.Cmd {
+	project := msg.project
+	status := m.gitStatus[project.Path]
+	status.isRepo = true
+	status.worktrees = msg.worktrees
+	m.gitStatus[project.Path] = status
+
+	m.projectExpanded[project.Path] = true
+	m.applyWorktrees()
+
+	for _, p := range m.projectItems {
+		if p.Path != project.Path {
+			continue
+		}
+		for _, node := range p.Children {
+			if _, ok := worktreeRoot([]gitWorktree{msg.worktree}, node.Cwd); !node.IsWorktree || !ok {
+				continue
+			}
+			m.selectProjectItem(node.Path)
+			m.updateInfoPane()
+			if profile, ok := defaultProfile(node, project.DefaultProfile); ok {
+				return m.launchProfile(profile)
+			}
+		}
+	}
+	return m.showToast("✓ Created worktree "+msg.worktree.path, false)
+}
+
+// viewWorktreePrompt renders the new worktree prompt centered over the screen
+func (m model) viewWorktreePrompt() string {
+	project := m.worktreeProject
+	path := "…"
+	if branch := strings.TrimSpace(m.worktreeBranch); branch != "" {
+		path = newWorktreeDir(project, m.gitStatus[project.Path], branch)
+	}
+
+	profile := "none"
+	if p, ok := defaultProfile(project, project.DefaultProfile); ok {
+		profile = p.Name
+	}
+
+	return m.viewPromptBox(
+		m.theme.heading.Render("New worktree for "+project.Name),
+		"",
+		promptField("Branch: ", m.worktreeBranch),
+		"",
+		"Directory: "+path,
+		"Then launch: "+profile,
+		"",
+		"Enter: create  Esc: cancel  (existing branches are checked out, new ones created)",
+	)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseWorktrees(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []gitWorktree
+	}{
+		{"empty output", "", nil},
+		{
+			"main and linked worktree",
+			"worktree /src/app\nHEAD 1234567890abcdef\nbranch refs/heads/main\n\n" +
+				"worktree /src/app-feat\nHEAD abcdef0123456789\nbranch refs/heads/feature/login\n",
+			[]gitWorktree{
+				{path: "/src/app", branch: "main", head: "1234567"},
+				{path: "/src/app-feat", branch: "feature/login", head: "abcdef0"},
+			},
+		},
+		{
+			"detached head",
+			"worktree /src/app\nHEAD 1234567890abcdef\ndetached\n",
+			[]gitWorktree{{path: "/src/app", head: "1234567"}},
+		},
+		{
+			"bare repository",
+			"worktree /src/app.git\nbare\n\nworktree /src/app-main\nHEAD 1234567890abcdef\nbranch refs/heads/main\n",
+			[]gitWorktree{
+				{path: "/src/app.git", bare: true},
+				{path: "/src/app-main", branch: "main", head: "1234567"},
+			},
+		},
+		{
+			"prunable worktree is left out",
+			"worktree /src/app\nHEAD 1234567890abcdef\nbranch refs/heads/main\n\n" +
+				"worktree /tmp/gone\nHEAD abcdef0123456789\nbranch refs/heads/old\nprunable gitdir file points to non-existent location\n\n" +
+				"worktree /src/app-b\nHEAD fedcba9876543210\nbranch refs/heads/b\n",
+			[]gitWorktree{
+				{path: "/src/app", branch: "main", head: "1234567"},
+				{path: "/src/app-b", branch: "b", head: "fedcba9"},
+			},
+		},
+		{
+			"locked worktree and paths with spaces",
+			"worktree /src/my app\nHEAD 1234567890abcdef\nbranch refs/heads/main\nlocked reason here\n",
+			[]gitWorktree{{path: "/src/my app", branch: "main", head: "1234567"}},
+		},
+		{
+			"malformed lines before any worktree are ignored",
+			"HEAD 1234567890abcdef\nbranch refs/heads/main\nbare\ngarbage\n",
+			nil,
+		},
+		{
+			"short HEAD is ignored",
+			"worktree /src/app\nHEAD 123\n",
+			[]gitWorktree{{path: "/src/app"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseWorktrees(tt.output)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("parseWorktrees() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorktreeRoot(t *testing.T) {
+	worktrees := []gitWorktree{
+		{path: "/src/app", branch: "main"},
+		{path: "/src/app/worktrees/feat", branch: "feat"},
+		{path: "/src/app-b", branch: "b"},
+	}
+
+	tests := []struct {
+		dir       string
+		want      string
+		wantFound bool
+	}{
+		{"/src/app", "/src/app", true},
+		{"/src/app/cmd/", "/src/app", true},
+		{"/src/app/worktrees/feat/pkg", "/src/app/worktrees/feat", true}, // Innermost wins
+		{"/src/app-b", "/src/app-b", true},                               // Not a prefix match of /src/app
+		{"/src/other", "", false},
+	}
+
+	for _, tt := range tests {
+		got, found := worktreeRoot(worktrees, tt.dir)
+		if found != tt.wantFound || got.path != tt.want {
+			t.Errorf("worktreeRoot(%q) = %q, %v; want %q, %v", tt.dir, got.path, found, tt.want, tt.wantFound)
+		}
+	}
+}
+
+func TestCheckBranchName(t *testing.T) {
+	dir, _ := newGitRepo(t)
+
+	tests := []struct {
+		branch  string
+		wantErr bool
+	}{
+		{"feat/login", false},
+		{"fix-123", false},
+		{"-b", true},
+		{"--force", true},
+		{"a..b", true},
+		{"has space", true},
+		{"ends.lock", true},
+		{"trailing/", true},
+	}
+
+	for _, tt := range tests {
+		err := checkBranchName(dir, tt.branch)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkBranchName(%q) = %v, want error %v", tt.branch, err, tt.wantErr)
+		}
+	}
+}
+
+func TestWorktreeAddArgs(t *testing.T) {
+	dir, git := newGitRepo(t)
+	git("branch", "existing")
+
+	tests := []struct {
+		branch string
+		want   []string
+	}{
+		{"feat", []string{"git", "-C", dir, "worktree", "add", "-b", "feat", "--", "/wt/feat"}},
+		{"existing", []string{"git", "-C", dir, "worktree", "add", "--", "/wt/existing", "existing"}},
+	}
+
+	for _, tt := range tests {
+		got := worktreeAddArgs(dir, tt.branch, "/wt/"+tt.branch)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("worktreeAddArgs(%q) = %q, want %q", tt.branch, got, tt.want)
+		}
+	}
+
+	// git takes both forms
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), tt.branch)
+		git(worktreeAddArgs(dir, tt.branch, path)[3:]...)
+	}
+}